@@ -0,0 +1,319 @@
+// Command wl-info prints the globals advertised by a Wayland
+// compositor, in the spirit of weston-info.  It binds the outputs, seats
+// and shm globals to describe them further.
+//
+// Usage:
+//
+//	wl-info [-json] [-display name]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dkolbly/wl"
+)
+
+// Info is what wl-info learns about a compositor.
+type Info struct {
+	Globals []*Global `json:"globals"`
+}
+
+// A Global is an advertised global, with what was learned by binding
+// it for the interfaces wl-info knows about.
+type Global struct {
+	Name      uint32  `json:"name"`
+	Interface string  `json:"interface"`
+	Version   uint32  `json:"version"`
+	Output    *Output `json:"output,omitempty"`
+	Seat      *Seat   `json:"seat,omitempty"`
+	Shm       *Shm    `json:"shm,omitempty"`
+}
+
+type Output struct {
+	X              int32              `json:"x"`
+	Y              int32              `json:"y"`
+	PhysicalWidth  int32              `json:"physical_width"`
+	PhysicalHeight int32              `json:"physical_height"`
+	Subpixel       wl.OutputSubpixel  `json:"subpixel"`
+	Make           string             `json:"make"`
+	Model          string             `json:"model"`
+	Transform      wl.OutputTransform `json:"transform"`
+	Scale          int32              `json:"scale"`
+	Modes          []Mode             `json:"modes"`
+}
+
+type Mode struct {
+	Width     int32 `json:"width"`
+	Height    int32 `json:"height"`
+	Refresh   int32 `json:"refresh"`
+	Current   bool  `json:"current"`
+	Preferred bool  `json:"preferred"`
+}
+
+type Seat struct {
+	Name         string            `json:"name,omitempty"`
+	Capabilities wl.SeatCapability `json:"capabilities"`
+}
+
+type Shm struct {
+	Formats []wl.ShmFormat `json:"formats"`
+}
+
+func main() {
+	jsonOut := flag.Bool("json", false, "print JSON")
+	display := flag.String("display", "", "the display to connect to, instead of $WAYLAND_DISPLAY")
+	flag.Parse()
+
+	info, err := collect(*display)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wl-info: %s\n", err)
+		os.Exit(1)
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(info)
+	} else {
+		err = info.Print(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wl-info: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// collect connects to the display and gathers its globals.
+func collect(addr string) (*Info, error) {
+	display, err := wl.Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+	c := display.Context()
+	defer c.Close()
+
+	registry, err := display.GetRegistry()
+	if err != nil {
+		return nil, err
+	}
+	info := new(Info)
+	registry.AddGlobalHandler(info)
+	if err := display.Roundtrip(context.Background()); err != nil {
+		return nil, err
+	}
+
+	for _, g := range info.Globals {
+		if err := g.bind(c, registry); err != nil {
+			return nil, err
+		}
+	}
+	if err := display.Roundtrip(context.Background()); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (info *Info) HandleRegistryGlobal(ev wl.RegistryGlobalEvent) {
+	info.Globals = append(info.Globals, &Global{
+		Name:      ev.Name,
+		Interface: ev.Interface,
+		Version:   ev.Version,
+	})
+}
+
+// bind binds the globals wl-info can describe, and sets up handlers
+// filling in their descriptions.
+func (g *Global) bind(c *wl.Context, registry *wl.Registry) error {
+	var proxy wl.Proxy
+	switch g.Interface {
+	case "wl_output":
+		g.Output = new(Output)
+		output := wl.NewOutput(c)
+		output.AddGeometryHandler(g.Output)
+		output.AddModeHandler(g.Output)
+		output.AddScaleHandler(g.Output)
+		proxy = output
+	case "wl_seat":
+		g.Seat = new(Seat)
+		seat := wl.NewSeat(c)
+		seat.AddCapabilitiesHandler(g.Seat)
+		seat.AddNameHandler(g.Seat)
+		proxy = seat
+	case "wl_shm":
+		g.Shm = new(Shm)
+		shm := wl.NewShm(c)
+		shm.AddFormatHandler(g.Shm)
+		proxy = shm
+	default:
+		return nil
+	}
+	version := g.Version
+	if v := proxy.Interface().Version; v < version {
+		version = v
+	}
+	return registry.Bind(g.Name, g.Interface, version, proxy)
+}
+
+func (o *Output) HandleOutputGeometry(ev wl.OutputGeometryEvent) {
+	o.X, o.Y = ev.X, ev.Y
+	o.PhysicalWidth, o.PhysicalHeight = ev.PhysicalWidth, ev.PhysicalHeight
+	o.Subpixel = ev.Subpixel
+	o.Make, o.Model = ev.Make, ev.Model
+	o.Transform = ev.Transform
+}
+
+func (o *Output) HandleOutputMode(ev wl.OutputModeEvent) {
+	o.Modes = append(o.Modes, Mode{
+		Width:     ev.Width,
+		Height:    ev.Height,
+		Refresh:   ev.Refresh,
+		Current:   ev.Flags.Has(wl.OutputModeCurrent),
+		Preferred: ev.Flags.Has(wl.OutputModePreferred),
+	})
+}
+
+func (o *Output) HandleOutputScale(ev wl.OutputScaleEvent) {
+	o.Scale = ev.Factor
+}
+
+func (s *Seat) HandleSeatCapabilities(ev wl.SeatCapabilitiesEvent) {
+	s.Capabilities = ev.Capabilities
+}
+
+func (s *Seat) HandleSeatName(ev wl.SeatNameEvent) {
+	s.Name = ev.Name
+}
+
+func (s *Shm) HandleShmFormat(ev wl.ShmFormatEvent) {
+	s.Formats = append(s.Formats, ev.Format)
+}
+
+// Print writes the information in the layout of weston-info.
+func (info *Info) Print(w io.Writer) error {
+	p := &printer{w: w}
+	for _, g := range info.Globals {
+		p.printf("interface: '%s', version: %d, name: %d\n", g.Interface, g.Version, g.Name)
+		if o := g.Output; o != nil {
+			p.printf("\tx: %d, y: %d, scale: %d,\n", o.X, o.Y, o.Scale)
+			p.printf("\tphysical_width: %d mm, physical_height: %d mm,\n", o.PhysicalWidth, o.PhysicalHeight)
+			p.printf("\tmake: '%s', model: '%s',\n", o.Make, o.Model)
+			p.printf("\tsubpixel_orientation: %s, output_transform: %s,\n",
+				subpixelName(o.Subpixel), transformName(o.Transform))
+			for _, m := range o.Modes {
+				p.printf("\tmode:\n")
+				p.printf("\t\twidth: %d px, height: %d px, refresh: %.3f Hz,\n",
+					m.Width, m.Height, float64(m.Refresh)/1000)
+				var flags []string
+				if m.Current {
+					flags = append(flags, "current")
+				}
+				if m.Preferred {
+					flags = append(flags, "preferred")
+				}
+				p.printf("\t\tflags: %s\n", strings.Join(flags, " "))
+			}
+		}
+		if s := g.Seat; s != nil {
+			if s.Name != "" {
+				p.printf("\tname: %s\n", s.Name)
+			}
+			p.printf("\tcapabilities: %s\n", capabilityNames(s.Capabilities))
+		}
+		if s := g.Shm; s != nil {
+			p.printf("\tformats:")
+			for _, f := range s.Formats {
+				p.printf(" %s", formatName(f))
+			}
+			p.printf("\n")
+		}
+	}
+	return p.err
+}
+
+// printer remembers the first write error, so that Print can check
+// once at the end.
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func subpixelName(v wl.OutputSubpixel) string {
+	switch v {
+	case wl.OutputSubpixelUnknown:
+		return "unknown"
+	case wl.OutputSubpixelNone:
+		return "none"
+	case wl.OutputSubpixelHorizontalRgb:
+		return "horizontal rgb"
+	case wl.OutputSubpixelHorizontalBgr:
+		return "horizontal bgr"
+	case wl.OutputSubpixelVerticalRgb:
+		return "vertical rgb"
+	case wl.OutputSubpixelVerticalBgr:
+		return "vertical bgr"
+	}
+	return fmt.Sprintf("unexpected value (%d)", v)
+}
+
+func transformName(v wl.OutputTransform) string {
+	switch v {
+	case wl.OutputTransformNormal:
+		return "normal"
+	case wl.OutputTransform90:
+		return "90°"
+	case wl.OutputTransform180:
+		return "180°"
+	case wl.OutputTransform270:
+		return "270°"
+	case wl.OutputTransformFlipped:
+		return "flipped"
+	case wl.OutputTransformFlipped90:
+		return "flipped 90°"
+	case wl.OutputTransformFlipped180:
+		return "flipped 180°"
+	case wl.OutputTransformFlipped270:
+		return "flipped 270°"
+	}
+	return fmt.Sprintf("unexpected value (%d)", v)
+}
+
+func capabilityNames(caps wl.SeatCapability) string {
+	var names []string
+	if caps.Has(wl.SeatCapabilityPointer) {
+		names = append(names, "pointer")
+	}
+	if caps.Has(wl.SeatCapabilityKeyboard) {
+		names = append(names, "keyboard")
+	}
+	if caps.Has(wl.SeatCapabilityTouch) {
+		names = append(names, "touch")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, " ")
+}
+
+// formatName returns the name of a shm format.  Apart from the two
+// formats every compositor supports, formats are DRM fourcc codes.
+func formatName(f wl.ShmFormat) string {
+	switch f {
+	case wl.ShmFormatArgb8888:
+		return "ARGB8888"
+	case wl.ShmFormatXrgb8888:
+		return "XRGB8888"
+	}
+	b := []byte{byte(f), byte(f >> 8), byte(f >> 16), byte(f >> 24)}
+	return strings.TrimRight(string(b), " ")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/wltest"
+)
+
+func TestCollect(t *testing.T) {
+	s := wltest.NewServer(t)
+	s.AddGlobal(wl.OutputInterface, 3)
+	s.Handle("wl_registry.bind", func(r *wltest.Request) {
+		output := r.Args[1].(*wltest.Object)
+		if output.Interface != wl.OutputInterface {
+			return
+		}
+		output.Send("geometry", int32(0), int32(0), int32(600), int32(340),
+			int32(wl.OutputSubpixelHorizontalRgb), "ACME", "Screen", int32(wl.OutputTransformNormal))
+		output.Send("mode", uint32(wl.OutputModeCurrent|wl.OutputModePreferred), int32(1920), int32(1080), int32(60000))
+		output.Send("scale", int32(2))
+		output.Send("done")
+	})
+
+	info, err := collect("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Globals) != 8 {
+		t.Fatalf("got %d globals", len(info.Globals))
+	}
+	var buf bytes.Buffer
+	if err := info.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"interface: 'wl_compositor', version: 4, name: 1\n",
+		"\tcapabilities: pointer keyboard\n",
+		"\tformats: ARGB8888 XRGB8888\n",
+		"\tx: 0, y: 0, scale: 2,\n",
+		"\tmake: 'ACME', model: 'Screen',\n",
+		"\t\twidth: 1920 px, height: 1080 px, refresh: 60.000 Hz,\n\t\tflags: current preferred\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatName(t *testing.T) {
+	// DRM_FORMAT_RGB565
+	if name := formatName(0x36314752); name != "RG16" {
+		t.Errorf("got %q", name)
+	}
+}
@@ -0,0 +1,113 @@
+// Command wl-proxy sits between Wayland clients and the compositor,
+// logging every message and optionally dropping or rewriting some of
+// them, to see how an application copes with the quirks of other
+// compositors.
+//
+// It listens on a display socket of its own and connects each client
+// to the compositor named by WAYLAND_DISPLAY:
+//
+//	wl-proxy -display wayland-proxy -hide wl_shell -scale 2 &
+//	WAYLAND_DISPLAY=wayland-proxy ./myapp
+//
+// Messages are decoded using the protocols generated in this module;
+// those of other protocols are passed through as they are.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// setFlag collects the values of a flag given several times.
+type setFlag map[string]bool
+
+func (f setFlag) String() string {
+	var names []string
+	for name := range f {
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
+
+func (f setFlag) Set(v string) error {
+	f[v] = true
+	return nil
+}
+
+func main() {
+	display := flag.String("display", "wayland-proxy", "the display socket to listen on")
+	upstream := flag.String("upstream", "", "the display of the compositor, instead of $WAYLAND_DISPLAY")
+	quiet := flag.Bool("q", false, "do not log messages")
+	filter := Filter{
+		Hide: make(setFlag),
+		Drop: make(setFlag),
+	}
+	flag.Var(setFlag(filter.Hide), "hide", "hide the globals of an `interface` (repeatable)")
+	flag.Var(setFlag(filter.Drop), "drop", "drop the `interface.message` messages (repeatable)")
+	scale := flag.Int("scale", 0, "announce every output with this scale")
+	flag.Parse()
+	filter.Scale = int32(*scale)
+
+	if *upstream == "" {
+		*upstream = os.Getenv("WAYLAND_DISPLAY")
+	}
+	if *upstream == "" {
+		*upstream = "wayland-0"
+	}
+	listen, err := socketPath(*display)
+	if err != nil {
+		fatal(err)
+	}
+	up, err := socketPath(*upstream)
+	if err != nil {
+		fatal(err)
+	}
+	if listen == up {
+		fatal(fmt.Errorf("the proxy cannot listen on the display of the compositor (%s)", up))
+	}
+
+	p := &Proxy{
+		Upstream: up,
+		Filter:   filter,
+	}
+	if !*quiet {
+		p.Log = log.New(os.Stderr, "", log.Lmicroseconds)
+	}
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: listen, Net: "unix"})
+	if err != nil {
+		fatal(err)
+	}
+	// remove the socket on the way out
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		ln.Close()
+	}()
+	fmt.Fprintf(os.Stderr, "wl-proxy: listening on %s\n", listen)
+	p.Serve(ln)
+}
+
+// socketPath resolves a display name like clients do.
+func socketPath(name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return name, nil
+	}
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		return "", fmt.Errorf("XDG_RUNTIME_DIR not set in the environment")
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "wl-proxy: %s\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+	// the protocols the proxy decodes register their interfaces
+	_ "github.com/dkolbly/wl/xdg"
+	_ "github.com/dkolbly/wl/xdg-unstable-v6"
+)
+
+// A value is a decoded argument.
+type value struct {
+	Arg *wl.Arg
+	// Uint holds ints, uints, fixed, objects and new ids
+	Uint   uint32
+	String string
+	Null   bool
+	Array  []byte
+	Fd     int
+	// Interface is the interface of a new id, which wl_registry.bind
+	// spells out.
+	Interface string
+	Version   uint32
+}
+
+// decode splits the arguments of a message.
+func decode(msg *wl.Message, data []byte, fds []int) ([]value, error) {
+	d := wire.Decoder{Data: data}
+	vals := make([]value, len(msg.Args))
+	for i := range msg.Args {
+		arg := &msg.Args[i]
+		v := &vals[i]
+		v.Arg = arg
+		switch arg.Type {
+		case wl.ArgInt, wl.ArgUint, wl.ArgFixed, wl.ArgObject:
+			v.Uint = d.Uint32()
+		case wl.ArgNewId:
+			v.Interface = arg.Interface
+			if arg.Interface == "" {
+				v.Interface = d.String()
+				v.Version = d.Uint32()
+			}
+			v.Uint = d.Uint32()
+		case wl.ArgString:
+			v.Array = d.Array()
+			v.Null = len(v.Array) == 0
+			v.String = strings.TrimRight(string(v.Array), "\x00")
+		case wl.ArgArray:
+			v.Array = d.Array()
+		case wl.ArgFd:
+			v.Fd = -1
+			if len(fds) > 0 {
+				v.Fd, fds = fds[0], fds[1:]
+			}
+		}
+	}
+	return vals, d.Err
+}
+
+// encode is the inverse of decode, for rewritten messages.
+func encode(vals []value) []byte {
+	var e wire.Encoder
+	for _, v := range vals {
+		switch v.Arg.Type {
+		case wl.ArgInt, wl.ArgUint, wl.ArgFixed, wl.ArgObject:
+			e.PutUint32(v.Uint)
+		case wl.ArgNewId:
+			if v.Arg.Interface == "" {
+				e.PutString(v.Interface)
+				e.PutUint32(v.Version)
+			}
+			e.PutUint32(v.Uint)
+		case wl.ArgString:
+			if v.Null {
+				e.PutUint32(0)
+			} else {
+				e.PutArray(append([]byte(v.String), 0))
+			}
+		case wl.ArgArray:
+			e.PutArray(v.Array)
+		}
+	}
+	return e.Data
+}
+
+// format prints the arguments like WAYLAND_DEBUG does.
+func format(vals []value, objects func(id uint32) string) string {
+	var b strings.Builder
+	for i, v := range vals {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		switch v.Arg.Type {
+		case wl.ArgInt:
+			fmt.Fprintf(&b, "%d", int32(v.Uint))
+		case wl.ArgUint:
+			fmt.Fprintf(&b, "%d", v.Uint)
+		case wl.ArgFixed:
+			fmt.Fprintf(&b, "%f", wl.Fixed(v.Uint).Float64())
+		case wl.ArgString:
+			if v.Null {
+				b.WriteString("nil")
+			} else {
+				fmt.Fprintf(&b, "%q", v.String)
+			}
+		case wl.ArgObject:
+			if v.Uint == 0 {
+				b.WriteString("nil")
+			} else {
+				fmt.Fprintf(&b, "%s@%d", objects(v.Uint), v.Uint)
+			}
+		case wl.ArgNewId:
+			if v.Arg.Interface == "" {
+				fmt.Fprintf(&b, "%q, %d, ", v.Interface, v.Version)
+			}
+			fmt.Fprintf(&b, "new id %s@%d", v.Interface, v.Uint)
+		case wl.ArgArray:
+			fmt.Fprintf(&b, "array[%d]", len(v.Array))
+		case wl.ArgFd:
+			fmt.Fprintf(&b, "fd %d", v.Fd)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+)
+
+// A Filter decides which messages go through the proxy, and how they
+// are changed on the way.
+type Filter struct {
+	// Hide lists interfaces whose globals are not announced to clients.
+	Hide map[string]bool
+	// Drop lists messages, such as "wl_surface.set_buffer_scale", that
+	// are not forwarded.
+	Drop map[string]bool
+	// Scale, if not zero, replaces the scale of every wl_output.
+	Scale int32
+}
+
+// A Proxy connects each of its clients to the compositor, decoding and
+// filtering all messages in between.
+type Proxy struct {
+	// Upstream is the path of the socket of the compositor.
+	Upstream string
+	Filter   Filter
+	// Log receives a line for every message, unless it is nil.
+	Log *log.Logger
+
+	mu      sync.Mutex
+	clients int
+}
+
+// Serve accepts clients on ln until it is closed.
+func (p *Proxy) Serve(ln *net.UnixListener) error {
+	for {
+		conn, err := ln.AcceptUnix()
+		if err != nil {
+			return err
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p *Proxy) handle(client *net.UnixConn) {
+	defer client.Close()
+	p.mu.Lock()
+	p.clients++
+	s := &session{
+		p:       p,
+		id:      p.clients,
+		objects: map[uint32]*wl.Interface{1: wl.DisplayInterface},
+		hidden:  make(map[uint32]bool),
+	}
+	p.mu.Unlock()
+
+	server, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: p.Upstream, Net: "unix"})
+	if err != nil {
+		s.logf("cannot reach the compositor: %s", err)
+		return
+	}
+	defer server.Close()
+	s.logf("connected")
+
+	done := make(chan struct{}, 2)
+	go func() {
+		s.forward(client, server, true)
+		done <- struct{}{}
+	}()
+	go func() {
+		s.forward(server, client, false)
+		done <- struct{}{}
+	}()
+	// when either side hangs up, so does the other
+	<-done
+	client.Close()
+	server.Close()
+	<-done
+	s.logf("disconnected")
+}
+
+// A session is the traffic of one client.
+type session struct {
+	p  *Proxy
+	id int
+
+	mu      sync.Mutex
+	objects map[uint32]*wl.Interface
+	// hidden holds the names of the globals hidden from the client
+	hidden map[uint32]bool
+}
+
+func (s *session) logf(format string, args ...interface{}) {
+	if s.p.Log != nil {
+		s.p.Log.Printf("client %d: %s", s.id, fmt.Sprintf(format, args...))
+	}
+}
+
+func (s *session) object(id uint32) *wl.Interface {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.objects[id]
+}
+
+func (s *session) objectName(id uint32) string {
+	if iface := s.object(id); iface != nil {
+		return iface.Name
+	}
+	return "[unknown]"
+}
+
+// forward copies the requests of the client to the compositor, or its
+// events back to the client.
+func (s *session) forward(from, to *net.UnixConn, requests bool) {
+	r := wire.NewReader(from)
+	defer r.Close()
+	arrow := "<-"
+	if requests {
+		arrow = "->"
+	}
+	for {
+		m, err := r.ReadMessage()
+		if err != nil {
+			return
+		}
+		iface := s.object(m.Id)
+		var msg *wl.Message
+		if iface != nil {
+			msgs := iface.Events
+			if requests {
+				msgs = iface.Requests
+			}
+			if m.Opcode < uint32(len(msgs)) {
+				msg = &msgs[m.Opcode]
+			}
+		}
+
+		var fds []int
+		if msg == nil {
+			// without a signature there is no telling which fds
+			// belong to the message, so pass on all there are
+			for fd, ok := r.TakeFd(); ok; fd, ok = r.TakeFd() {
+				fds = append(fds, fd)
+			}
+			s.logf("%s %s@%d.[opcode %d]()", arrow, s.objectName(m.Id), m.Id, m.Opcode)
+		} else {
+			for _, arg := range msg.Args {
+				if arg.Type != wl.ArgFd {
+					continue
+				}
+				if fd, ok := r.TakeFd(); ok {
+					fds = append(fds, fd)
+				}
+			}
+			keep := s.filter(iface, msg, m, fds, arrow)
+			if !keep {
+				closeAll(fds)
+				continue
+			}
+		}
+
+		e := wire.Encoder{Data: m.Data, Fds: fds}
+		err = e.WriteMessage(to, m.Id, m.Opcode)
+		closeAll(fds)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// filter tracks the objects created by a message, logs it and applies
+// the Filter, rewriting m.Data as needed.  It reports whether the
+// message should be forwarded.
+func (s *session) filter(iface *wl.Interface, msg *wl.Message, m *wire.Message, fds []int, arrow string) bool {
+	name := iface.Name + "." + msg.Name
+	vals, err := decode(msg, m.Data, fds)
+	if err != nil {
+		s.logf("%s %s@%d.%s: %s", arrow, iface.Name, m.Id, msg.Name, err)
+		return true
+	}
+
+	s.mu.Lock()
+	for _, v := range vals {
+		if v.Arg.Type == wl.ArgNewId && v.Uint != 0 {
+			s.objects[v.Uint] = wl.LookupInterface(v.Interface)
+		}
+	}
+	if name == "wl_display.delete_id" {
+		delete(s.objects, vals[0].Uint)
+	}
+	keep, rewritten := true, false
+	f := &s.p.Filter
+	switch {
+	case f.Drop[name]:
+		keep = false
+	case name == "wl_registry.global" && f.Hide[vals[1].String]:
+		s.hidden[vals[0].Uint] = true
+		keep = false
+	case name == "wl_registry.global_remove" && s.hidden[vals[0].Uint]:
+		keep = false
+	case name == "wl_output.scale" && f.Scale != 0:
+		vals[0].Uint = uint32(f.Scale)
+		rewritten = true
+	}
+	s.mu.Unlock()
+
+	if rewritten {
+		m.Data = encode(vals)
+	}
+	if s.p.Log != nil {
+		prefix, suffix := "", ""
+		if !keep {
+			prefix = "dropped "
+		}
+		if rewritten {
+			suffix = " (rewritten)"
+		}
+		s.logf("%s %s%s@%d.%s(%s)%s", arrow, prefix, iface.Name, m.Id, msg.Name, format(vals, s.objectName), suffix)
+	}
+	return keep
+}
+
+func closeAll(fds []int) {
+	for _, fd := range fds {
+		syscall.Close(fd)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/wltest"
+)
+
+// startProxy runs a proxy in front of a fake compositor, and returns
+// the name of its display.
+func startProxy(t *testing.T, s *wltest.Server, filter Filter) (string, *syncBuffer) {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: filepath.Join(dir, "wayland-proxy"), Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	buf := new(syncBuffer)
+	p := &Proxy{
+		Upstream: filepath.Join(dir, s.Display()),
+		Filter:   filter,
+		Log:      log.New(buf, "", 0),
+	}
+	go p.Serve(ln)
+	return "wayland-proxy", buf
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+type globals struct {
+	names map[string]uint32
+	scale int32
+}
+
+func (g *globals) HandleRegistryGlobal(ev wl.RegistryGlobalEvent) {
+	g.names[ev.Interface] = ev.Name
+}
+
+func (g *globals) HandleOutputScale(ev wl.OutputScaleEvent) {
+	g.scale = ev.Factor
+}
+
+func connect(t *testing.T, name string) (*wl.Display, *wl.Registry, *globals) {
+	display, err := wl.Connect(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { display.Context().Close() })
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &globals{names: make(map[string]uint32)}
+	registry.AddGlobalHandler(g)
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return display, registry, g
+}
+
+func TestFilter(t *testing.T) {
+	s := wltest.NewServer(t)
+	s.AddGlobal(wl.OutputInterface, 2)
+	s.Handle("wl_registry.bind", func(r *wltest.Request) {
+		if output := r.Args[1].(*wltest.Object); output.Interface == wl.OutputInterface {
+			output.Send("scale", int32(1))
+			output.Send("done")
+		}
+	})
+	name, logged := startProxy(t, s, Filter{
+		Hide:  map[string]bool{"wl_shell": true},
+		Drop:  map[string]bool{"wl_surface.set_buffer_scale": true},
+		Scale: 2,
+	})
+
+	display, registry, g := connect(t, name)
+	if _, ok := g.names["wl_shell"]; ok {
+		t.Error("wl_shell was not hidden")
+	}
+	if len(g.names) != 7 {
+		t.Errorf("unexpected globals %v", g.names)
+	}
+
+	c := display.Context()
+	output := wl.NewOutput(c)
+	output.AddScaleHandler(g)
+	registry.Bind(g.names["wl_output"], "wl_output", 2, output)
+	compositor := wl.NewCompositor(c)
+	registry.Bind(g.names["wl_compositor"], "wl_compositor", 4, compositor)
+	surface, _ := compositor.CreateSurface()
+	surface.SetBufferScale(2)
+	surface.Commit()
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if g.scale != 2 {
+		t.Errorf("scale %d was not rewritten", g.scale)
+	}
+	s.Next("wl_surface.commit")
+	for _, r := range s.Requests() {
+		if r.Name() == "wl_surface.set_buffer_scale" {
+			t.Error("set_buffer_scale was not dropped")
+		}
+	}
+
+	out := logged.String()
+	for _, want := range []string{
+		`client 1: <- dropped wl_registry@2.global(6, "wl_shell", 1)`,
+		// the ids depend on whether the id of the first callback
+		// was released before the binds
+		fmt.Sprintf("client 1: <- wl_output@%d.scale(2) (rewritten)", output.Id()),
+		fmt.Sprintf("client 1: -> wl_compositor@%d.create_surface(new id wl_surface@%d)", compositor.Id(), surface.Id()),
+		fmt.Sprintf("client 1: -> dropped wl_surface@%d.set_buffer_scale(2)", surface.Id()),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFds(t *testing.T) {
+	s := wltest.NewServer(t)
+	name, _ := startProxy(t, s, Filter{})
+
+	display, registry, g := connect(t, name)
+	c := display.Context()
+	shm := wl.NewShm(c)
+	registry.Bind(g.names["wl_shm"], "wl_shm", 1, shm)
+	f, err := ioutil.TempFile(t.TempDir(), "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString("pool")
+	shm.CreatePool(f.Fd(), 4)
+	c.Flush()
+
+	got := s.Next("wl_shm.create_pool").Args[1].(*os.File)
+	buf := make([]byte, 4)
+	if _, err := got.ReadAt(buf, 0); err != nil || string(buf) != "pool" {
+		t.Errorf("read %q, %v through the passed fd", buf, err)
+	}
+}
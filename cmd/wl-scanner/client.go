@@ -0,0 +1,558 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// go types
+type (
+	GoInterface struct {
+		Name     string
+		WL       string
+		WlName   string
+		Version  int
+		Requests []GoRequest
+		Events   []GoEvent
+		Enums    []GoEnum
+	}
+
+	GoRequest struct {
+		WL             string
+		Name           string
+		WlName         string
+		IfaceName      string
+		Params         string
+		Returns        string
+		Args           string
+		HasNewId       bool
+		NewIdInterface string
+		Destructor     bool
+		Since          int
+		Order          int
+		Summary        string
+		Description    string
+		Meta           []GoMetaArg
+	}
+
+	GoEvent struct {
+		WL        string
+		Name      string
+		WlName    string
+		IfaceName string
+		PName     string
+		EName     string
+		FuncName  string
+		Since     int
+		Args      []GoArg
+		// NewId holds the decoding of the objects the event
+		// creates, which happens whether anybody listens or not
+		NewId []string
+		// Decode holds the decoding of the other arguments
+		Decode []string
+		Meta   []GoMetaArg
+	}
+
+	GoArg struct {
+		Name string
+		Type string
+	}
+
+	// GoMetaArg is an argument in the protocol metadata.
+	GoMetaArg struct {
+		Name      string
+		Type      string
+		Interface string
+		AllowNull bool
+	}
+
+	GoEnum struct {
+		Name      string
+		WlName    string
+		IfaceName string
+		Type      string
+		BitField  bool
+		Entries   []GoEntry
+	}
+
+	GoEntry struct {
+		Name   string
+		WlName string
+		Value  string
+	}
+)
+
+var (
+	wlTypes = map[string]string{
+		"int":    "int32",
+		"uint":   "uint32",
+		"string": "string",
+		"fd":     "uintptr",
+		"fixed":  "Fixed",
+		"array":  "[]byte",
+	}
+
+	// sync with event.go
+	bufTypesMap = map[string]string{
+		"int":    "Int32()",
+		"uint":   "Uint32()",
+		"string": "String()",
+		"fixed":  "Fixed()",
+		"array":  "Array()",
+		"fd":     "FD()",
+	}
+
+	// argTypes are the names of the ArgType constants
+	argTypes = map[string]string{
+		"int":    "ArgInt",
+		"uint":   "ArgUint",
+		"fixed":  "ArgFixed",
+		"string": "ArgString",
+		"object": "ArgObject",
+		"new_id": "ArgNewId",
+		"array":  "ArgArray",
+		"fd":     "ArgFd",
+	}
+)
+
+func generateClient(protocol *Protocol) {
+	fmt.Fprintf(fileBuffer, "import (\n")
+	fmt.Fprintf(fileBuffer, "     \"sync\"\n")
+	if *pkgName != "wl" {
+		fmt.Fprintf(fileBuffer, "     \"github.com/dkolbly/wl\"\n")
+	}
+	fmt.Fprintf(fileBuffer, ")\n")
+
+	var ifaces []GoInterface
+	for _, iface := range protocol.Interfaces {
+		goIface := GoInterface{
+			Name:    wlNames[iface.Name],
+			WlName:  iface.Name,
+			Version: iface.Version,
+			WL:      wlPrefix,
+		}
+
+		goIface.ProcessEvents(iface)
+		goIface.Constructor()
+		goIface.ProcessEnums(iface)
+		goIface.ProcessRequests(iface)
+		for _, goEnum := range goIface.Enums {
+			executeTemplate("InterfaceEnumsTemplate", ifaceEnums, goEnum)
+		}
+		ifaces = append(ifaces, goIface)
+	}
+	executeTemplate("ClientRegisterTemplate", clientRegisterTemplate, ifaces)
+}
+
+func (i *GoInterface) Constructor() {
+	executeTemplate("InterfaceTypeTemplate", ifaceTypeTemplate, i)
+	executeTemplate("InterfaceConstructorTemplate", ifaceConstructorTemplate, i)
+}
+
+// metaArgs describes the arguments of a message for the metadata.
+func metaArgs(args []Arg) []GoMetaArg {
+	var ret []GoMetaArg
+	for _, arg := range args {
+		t, ok := argTypes[arg.Type]
+		if !ok {
+			log.Fatalf("unknown argument type %s", arg.Type)
+		}
+		ret = append(ret, GoMetaArg{
+			Name:      arg.Name,
+			Type:      wlPrefix + t,
+			Interface: arg.Interface,
+			AllowNull: arg.AllowNull,
+		})
+	}
+	return ret
+}
+
+func since(v int) int {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// goType returns the type of an int, uint, fixed, string, fd or array
+// argument of a message of iface, and the conversion of its wire value
+// to that type.
+func goType(iface, message string, arg Arg) (typ, conv string) {
+	if arg.Type == "array" {
+		switch elem := arrayTypes[iface+"."+message+"."+arg.Name]; elem {
+		case "":
+		case "uint32":
+			return "[]uint32", wlPrefix + "Uint32s"
+		default:
+			return "[]" + enumType(iface, elem), "[]"
+		}
+	}
+	if arg.Enum != "" && (arg.Type == "int" || arg.Type == "uint") {
+		t := enumType(iface, arg.Enum)
+		return t, t
+	}
+	t := wlTypes[arg.Type]
+	if t == "Fixed" {
+		t = wlPrefix + t
+	}
+	return t, ""
+}
+
+func (i *GoInterface) ProcessRequests(iface Interface) {
+	for order, wlReq := range iface.Requests {
+		var (
+			returns         []string
+			params          []string
+			sendRequestArgs []string // for sendRequest
+		)
+
+		req := GoRequest{
+			WL:          wlPrefix,
+			Name:        CamelCase(wlReq.Name),
+			WlName:      wlReq.Name,
+			IfaceName:   i.Name,
+			Order:       order,
+			Since:       since(wlReq.Since),
+			Destructor:  wlReq.Type == "destructor",
+			Summary:     wlReq.Description.Summary,
+			Description: reflow(wlReq.Description.Text),
+			Meta:        metaArgs(wlReq.Args),
+		}
+
+		for _, arg := range wlReq.Args {
+			if arg.Type == "new_id" {
+				if arg.Interface != "" {
+					newIdIface := wlNames[arg.Interface]
+					req.NewIdInterface = newIdIface
+					sendRequestArgs = append(sendRequestArgs, wlPrefix+"Proxy(ret)")
+					req.HasNewId = true
+
+					returns = append(returns, "*"+newIdIface)
+				} else { //special for registry.Bind
+					sendRequestArgs = append(sendRequestArgs, "iface")
+					sendRequestArgs = append(sendRequestArgs, "version")
+					sendRequestArgs = append(sendRequestArgs, arg.Name)
+
+					params = append(params, "iface string")
+					params = append(params, "version uint32")
+					params = append(params, fmt.Sprintf("%s %sProxy", arg.Name, wlPrefix))
+				}
+			} else if arg.Type == "object" && arg.Interface != "" {
+				paramTypeName := wlNames[arg.Interface]
+				params = append(params, fmt.Sprintf("%s *%s", arg.Name, paramTypeName))
+				sendRequestArgs = append(sendRequestArgs, arg.Name)
+			} else {
+				t, conv := goType(iface.Name, wlReq.Name, arg)
+				params = append(params, fmt.Sprintf("%s %s", arg.Name, t))
+				switch conv {
+				case "":
+					sendRequestArgs = append(sendRequestArgs, arg.Name)
+				default:
+					sendRequestArgs = append(sendRequestArgs, fmt.Sprintf("%s(%s)", wlTypes[arg.Type], arg.Name))
+				}
+			}
+		}
+
+		req.Params = strings.Join(params, ",")
+
+		if len(sendRequestArgs) > 0 {
+			req.Args = "," + strings.Join(sendRequestArgs, ",")
+		}
+
+		if len(returns) > 0 { // ( ret , error )
+			req.Returns = fmt.Sprintf("(%s , error)", strings.Join(returns, ","))
+		} else { // returns only error
+			req.Returns = "error"
+		}
+
+		i.Requests = append(i.Requests, req)
+	}
+
+	executeTemplate("InterfaceMetadataTemplate", ifaceMetadataTemplate, i)
+	for _, req := range i.Requests {
+		executeTemplate("RequestTemplate", requestTemplate, req)
+	}
+}
+
+func (i *GoInterface) ProcessEvents(iface Interface) {
+	// Event struct types
+	for _, wlEv := range iface.Events {
+		ev := GoEvent{
+			Name:      CamelCase(wlEv.Name),
+			WlName:    wlEv.Name,
+			PName:     snakeCase(wlEv.Name),
+			IfaceName: i.Name,
+			Since:     since(wlEv.Since),
+			WL:        wlPrefix,
+			Meta:      metaArgs(wlEv.Args),
+		}
+		ev.EName = i.Name + ev.Name
+		ev.FuncName = lowerFirst(ev.EName) + "Func"
+
+		for _, arg := range wlEv.Args {
+			goarg := GoArg{
+				Name: CamelCase(arg.Name),
+			}
+			field := "ev." + goarg.Name
+			switch {
+			case arg.Type == "new_id" && arg.Interface != "":
+				t := wlNames[arg.Interface]
+				goarg.Type = "*" + t
+				ev.NewId = append(ev.NewId, fmt.Sprintf("%s = event.NewId(p.Context(), new(%s)).(*%s)", field, t, t))
+			case arg.Type == "object" && arg.Interface != "":
+				goarg.Type = "*" + wlNames[arg.Interface]
+				ev.Decode = append(ev.Decode, fmt.Sprintf("%s, _ = event.Proxy(p.Context()).(%s)", field, goarg.Type))
+			case arg.Type == "object" || arg.Type == "new_id":
+				goarg.Type = wlPrefix + "Proxy"
+				ev.Decode = append(ev.Decode, fmt.Sprintf("%s = event.Proxy(p.Context())", field))
+			default:
+				bufMethod, ok := bufTypesMap[arg.Type]
+				if !ok {
+					log.Fatalf("%s not registered", arg.Type)
+				}
+				t, conv := goType(iface.Name, wlEv.Name, arg)
+				goarg.Type = t
+				switch conv {
+				case "":
+					ev.Decode = append(ev.Decode, fmt.Sprintf("%s = event.%s", field, bufMethod))
+				case "[]":
+					ev.Decode = append(ev.Decode,
+						fmt.Sprintf("for _, v := range %sUint32s(event.%s) {", wlPrefix, bufMethod),
+						fmt.Sprintf("\t%s = append(%s, %s(v))", field, field, t[2:]),
+						"}")
+				default:
+					ev.Decode = append(ev.Decode, fmt.Sprintf("%s = %s(event.%s)", field, conv, bufMethod))
+				}
+			}
+
+			ev.Args = append(ev.Args, goarg)
+		}
+
+		executeTemplate("EventTemplate", eventTemplate, ev)
+		executeTemplate("AddRemoveHandlerTemplate", ifaceAddRemoveHandlerTemplate, ev)
+
+		i.Events = append(i.Events, ev)
+	}
+
+	if len(i.Events) > 0 {
+		executeTemplate("InterfaceDispatchTemplate", ifaceDispatchTemplate, i)
+	}
+}
+
+func (i *GoInterface) ProcessEnums(iface Interface) {
+	// Enums - Constants
+	for _, wlEnum := range iface.Enums {
+		goEnum := GoEnum{
+			Name:      CamelCase(wlEnum.Name),
+			WlName:    wlEnum.Name,
+			IfaceName: i.Name,
+			BitField:  wlEnum.BitField,
+		}
+		goEnum.Type = i.Name + goEnum.Name
+
+		for _, wlEntry := range wlEnum.Entries {
+			goEntry := GoEntry{
+				Name:   CamelCase(wlEntry.Name),
+				WlName: wlEntry.Name,
+				Value:  wlEntry.Value,
+			}
+			goEnum.Entries = append(goEnum.Entries, goEntry)
+		}
+
+		i.Enums = append(i.Enums, goEnum)
+	}
+}
+
+// templates
+var (
+	ifaceTypeTemplate = `
+type {{.Name}} struct {
+	{{.WL}}BaseProxy
+	{{- if gt (len .Events) 0 }}
+	mu sync.RWMutex
+	{{- end}}
+
+	{{- range .Events}}
+	{{.PName}}Handlers []{{.EName}}Handler
+	{{- end}}
+}
+`
+	ifaceConstructorTemplate = `
+func New{{.Name}}(ctx *{{.WL}}Context) *{{.Name}} {
+	ret := new({{.Name}})
+	ctx.Register(ret)
+	return ret
+}
+`
+	ifaceMetadataTemplate = `
+{{- define "args"}}
+	{{- if .Meta}}, Args: []{{.WL}}Arg{
+	{{- range .Meta}}
+		{Name: "{{.Name}}", Type: {{.Type}}
+		{{- if .Interface}}, Interface: "{{.Interface}}"{{end}}
+		{{- if .AllowNull}}, AllowNull: true{{end}}},
+	{{- end}}
+	}{{end}}
+{{- end}}
+// {{.Name}}Interface describes the {{.WlName}} interface.
+var {{.Name}}Interface = &{{.WL}}Interface{
+	Name:    "{{.WlName}}",
+	Version: {{.Version}},
+	{{- if .Requests}}
+	Requests: []{{.WL}}Message{
+		{{- range .Requests}}
+		{Name: "{{.WlName}}", Since: {{.Since}}
+		{{- if .Destructor}}, Destructor: true{{end}}
+		{{- template "args" .}}},
+		{{- end}}
+	},
+	{{- end}}
+	{{- if .Events}}
+	Events: []{{.WL}}Message{
+		{{- range .Events}}
+		{Name: "{{.WlName}}", Since: {{.Since}}
+		{{- template "args" .}}},
+		{{- end}}
+	},
+	{{- end}}
+	{{- if .Enums}}
+	Enums: []{{.WL}}Enum{
+		{{- range .Enums}}
+		{Name: "{{.WlName}}"{{if .BitField}}, Bitfield: true{{end}}, Entries: []{{$.WL}}EnumEntry{
+			{{- range .Entries}}
+			{Name: "{{.WlName}}", Value: {{.Value}}},
+			{{- end}}
+		}},
+		{{- end}}
+	},
+	{{- end}}
+}
+
+func (p *{{.Name}}) Interface() *{{.WL}}Interface {
+	return {{.Name}}Interface
+}
+`
+	ifaceAddRemoveHandlerTemplate = `
+func (p *{{.IfaceName}}) Add{{.Name}}Handler(h {{.EName}}Handler) {
+	if h != nil {
+		p.mu.Lock()
+		p.{{.PName}}Handlers = append(p.{{.PName}}Handlers , h)
+		p.mu.Unlock()
+	}
+}
+
+func (p *{{.IfaceName}}) Remove{{.Name}}Handler(h {{.EName}}Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i , e := range p.{{.PName}}Handlers {
+		if e == h {
+			p.{{.PName}}Handlers = append(p.{{.PName}}Handlers[:i:i] , p.{{.PName}}Handlers[i+1:]...)
+			break
+		}
+	}
+}
+
+type {{.FuncName}} func({{.EName}}Event)
+
+func (f *{{.FuncName}}) Handle{{.EName}}(ev {{.EName}}Event) {
+	(*f)(ev)
+}
+
+func (p *{{.IfaceName}}) On{{.Name}}(f func({{.EName}}Event)) (cancel func()) {
+	h := {{.FuncName}}(f)
+	p.Add{{.Name}}Handler(&h)
+	return func() { p.Remove{{.Name}}Handler(&h) }
+}
+`
+
+	requestTemplate = `
+// {{.Name}} will {{.Summary}}.
+//
+{{.Description}}func (p *{{.IfaceName}}) {{.Name}}({{.Params}}) {{.Returns}} {
+	{{- if .HasNewId}}
+	ret := New{{.NewIdInterface}}(p.Context())
+	return ret , p.Context().SendRequest(p,{{.Order}}{{.Args}})
+	{{- else if .Destructor}}
+	err := p.Context().SendRequest(p,{{.Order}}{{.Args}})
+	p.Context().Unregister(p)
+	return err
+	{{- else}}
+	return p.Context().SendRequest(p,{{.Order}}{{.Args}})
+	{{- end}}
+}
+`
+
+	eventTemplate = `
+type {{.IfaceName}}{{.Name}}Event struct {
+	{{- range .Args }}
+	{{.Name}} {{.Type}}
+	{{- end }}
+}
+
+type {{.IfaceName}}{{.Name}}Handler interface {
+    Handle{{.EName}}({{.EName}}Event)
+}
+`
+
+	ifaceDispatchTemplate = `
+func (p *{{.Name}}) Dispatch(event *{{.WL}}Event) {
+	{{- $ifaceName := .Name }}
+	switch event.Opcode {
+	{{- range $i , $event := .Events }}
+	case {{$i}}:
+		{{- if .NewId}}
+		ev := {{$ifaceName}}{{.Name}}Event{}
+		{{- range .NewId}}
+		{{.}}
+		{{- end}}
+		{{- end}}
+		p.mu.RLock()
+		handlers := p.{{.PName}}Handlers
+		p.mu.RUnlock()
+		if len(handlers) > 0 {
+			{{- if not .NewId}}
+			ev := {{$ifaceName}}{{.Name}}Event{}
+			{{- end}}
+			{{- range .Decode}}
+			{{.}}
+			{{- end}}
+			for _, h := range handlers {
+				event.Call(func() { h.Handle{{.EName}}(ev) })
+			}
+		}
+	{{- end}}
+	}
+}
+`
+	ifaceEnums = `
+type {{.Type}} uint32
+
+const (
+	{{- $type := .Type }}
+	{{- range .Entries}}
+	{{$type}}{{.Name}} {{$type}} = {{.Value}}
+	{{- end}}
+)
+
+func (e {{.Type}}) String() string {
+	return {{.IfaceName}}Interface.Enum("{{.WlName}}").Format(uint32(e))
+}
+{{- if .BitField}}
+
+// Has reports whether all of flags are set.
+func (e {{.Type}}) Has(flags {{.Type}}) bool {
+	return e&flags == flags
+}
+{{- end}}
+`
+
+	clientRegisterTemplate = `
+func init() {
+	{{- range .}}
+	{{.WL}}RegisterInterface({{.Name}}Interface, func() {{.WL}}Proxy { return new({{.Name}}) })
+	{{- end}}
+}
+`
+)
@@ -0,0 +1,283 @@
+// wl-scanner started out as github.com/dkolbly/wl-scanner,
+// Copyright (c) 2016, sternix, under the BSD 2-Clause license in the
+// LICENSE file of this directory.
+
+// Command wl-scanner generates the Go bindings of a wayland protocol
+// from its XML specification: the client proxies of package wl and of
+// the protocol packages like xdg, or with -server the resources of
+// package server.  It is run by go generate; see the //go:generate
+// lines of the packages for the invocations.
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+var (
+	source   = flag.String("source", "", "The XML protocol file")
+	output   = flag.String("output", "", "Where to put the output go file")
+	pkgName  = flag.String("pkg", "wl", "Name of the package")
+	unstable = flag.String("unstable", "", "Unstable suffix name to strip (e.g., v6)")
+	server   = flag.Bool("server", false, "Generate the server side resources")
+	client   = flag.String("client", "github.com/dkolbly/wl", "Import path of the client package, whose protocol metadata the server side uses")
+)
+
+// xml types
+type Protocol struct {
+	XMLName    xml.Name    `xml:"protocol"`
+	Name       string      `xml:"name,attr"`
+	Copyright  string      `xml:"copyright"`
+	Interfaces []Interface `xml:"interface"`
+}
+
+type Description struct {
+	XMLName xml.Name `xml:"description"`
+	Summary string   `xml:"summary,attr"`
+	Text    string   `xml:",chardata"`
+}
+
+type Interface struct {
+	XMLName     xml.Name    `xml:"interface"`
+	Name        string      `xml:"name,attr"`
+	Version     int         `xml:"version,attr"`
+	Description Description `xml:"description"`
+	Requests    []Message   `xml:"request"`
+	Events      []Message   `xml:"event"`
+	Enums       []Enum      `xml:"enum"`
+}
+
+// Message is a request or an event.
+type Message struct {
+	Name        string      `xml:"name,attr"`
+	Type        string      `xml:"type,attr"`
+	Since       int         `xml:"since,attr"`
+	Description Description `xml:"description"`
+	Args        []Arg       `xml:"arg"`
+}
+
+type Arg struct {
+	XMLName   xml.Name `xml:"arg"`
+	Name      string   `xml:"name,attr"`
+	Type      string   `xml:"type,attr"`
+	Interface string   `xml:"interface,attr"`
+	Enum      string   `xml:"enum,attr"`
+	AllowNull bool     `xml:"allow-null,attr"`
+	Summary   string   `xml:"summary,attr"`
+}
+
+type Enum struct {
+	XMLName     xml.Name    `xml:"enum"`
+	Name        string      `xml:"name,attr"`
+	BitField    bool        `xml:"bitfield,attr"`
+	Description Description `xml:"description"`
+	Entries     []Entry     `xml:"entry"`
+}
+
+type Entry struct {
+	XMLName xml.Name `xml:"entry"`
+	Name    string   `xml:"name,attr"`
+	Value   string   `xml:"value,attr"`
+	Summary string   `xml:"summary,attr"`
+}
+
+// arrayTypes gives the element type of the array arguments that the
+// protocols document as holding uint32 values, or values of an enum.
+var arrayTypes = map[string]string{
+	"wl_keyboard.enter.keys":            "uint32",
+	"xdg_toplevel.configure.states":     "state",
+	"zxdg_toplevel_v6.configure.states": "state",
+}
+
+var inheritedNames = []string{
+	"wl_display",
+	"wl_registry",
+	"wl_callback",
+	"wl_compositor",
+	"wl_shm_pool",
+	"wl_shm",
+	"wl_buffer",
+	"wl_data_offer",
+	"wl_data_source",
+	"wl_data_device",
+	"wl_data_device_manager",
+	"wl_shell",
+	"wl_shell_surface",
+	"wl_surface",
+	"wl_seat",
+	"wl_pointer",
+	"wl_keyboard",
+	"wl_touch",
+	"wl_output",
+	"wl_region",
+	"wl_subcompositor",
+	"wl_subsurface",
+}
+
+var (
+	// wlNames maps the interfaces to their Go types
+	wlNames = make(map[string]string)
+	// wlPrefix qualifies the names of package wl, and metaPrefix the
+	// interfaces and enums of the client package the metadata is in
+	wlPrefix   string
+	metaPrefix string
+	fileBuffer = &bytes.Buffer{}
+)
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	if *source == "" {
+		log.Fatal("Must specify a -source")
+	}
+	if *output == "" {
+		log.Fatal("Must specify -output")
+	}
+
+	f, err := os.Open(*source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var protocol Protocol
+	err = xml.NewDecoder(f).Decode(&protocol)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Cannot decode %s: %s", *source, err)
+	}
+
+	if *pkgName != "wl" {
+		wlPrefix = "wl."
+	}
+	if protocol.Name != "wayland" {
+		trimPrefix = *pkgName + "_"
+		for _, inherit := range inheritedNames {
+			wlNames[inherit] = "wl." + CamelCase(strings.TrimPrefix(inherit, "wl_"))
+		}
+	}
+	if *unstable != "" {
+		ifTrimSuffix = "_" + *unstable
+	}
+	for _, iface := range protocol.Interfaces {
+		wlNames[iface.Name] = CamelCase(stripUnstable(iface.Name))
+	}
+
+	fmt.Fprintf(fileBuffer, "// Code generated by wl-scanner from %s. DO NOT EDIT.\n\n", *source)
+	fmt.Fprintf(fileBuffer, "package %s\n", *pkgName)
+	if *server {
+		generateServer(&protocol)
+	} else {
+		generateClient(&protocol)
+	}
+
+	src, err := format.Source(fileBuffer.Bytes())
+	if err != nil {
+		log.Fatalf("formatting the output: %s", err)
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func executeTemplate(name string, tpl string, data interface{}) {
+	tmpl := template.Must(template.New(name).Parse(tpl))
+	err := tmpl.Execute(fileBuffer, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// enumType returns the Go type of the enum an argument refers to,
+// which is either "enum" of the interface or "interface.enum".
+func enumType(iface, enum string) string {
+	if i := strings.IndexByte(enum, '.'); i >= 0 {
+		iface, enum = enum[:i], enum[i+1:]
+	}
+	return qualifyMeta(wlNames[iface]) + CamelCase(enum)
+}
+
+// qualifyMeta returns name, the name of a type of the client package,
+// as seen from the generated one.
+func qualifyMeta(name string) string {
+	if metaPrefix == "" || strings.HasPrefix(name, "wl.") {
+		return name
+	}
+	return metaPrefix + name
+}
+
+// metaAlias returns the name the server side imports the client
+// package under.
+func metaAlias() string {
+	if *client == "github.com/dkolbly/wl" {
+		return "wl"
+	}
+	return "wl" + path.Base(*client)
+}
+
+var trimPrefix = "wl_"
+var ifTrimSuffix = ""
+
+func CamelCase(wlName string) string {
+	wlName = strings.TrimPrefix(wlName, trimPrefix)
+
+	var b strings.Builder
+	for _, part := range strings.Split(wlName, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+func snakeCase(wlName string) string {
+	wlName = strings.TrimPrefix(wlName, "wl_")
+
+	parts := strings.Split(wlName, "_")
+	for i, p := range parts {
+		if i > 0 && p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// goKeywords are the argument names that need renaming as parameters.
+var goKeywords = map[string]bool{
+	"interface": true, "type": true, "func": true, "range": true,
+	"map": true, "select": true, "default": true, "go": true,
+	"var": true, "chan": true,
+}
+
+// paramName returns the Go parameter for an argument.
+func paramName(arg string) string {
+	name := snakeCase(arg)
+	if goKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func reflow(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	ret := ""
+	for _, line := range lines {
+		ret = ret + "// " + strings.TrimSpace(line) + "\n"
+	}
+	return ret
+}
+
+func stripUnstable(ifname string) string {
+	return strings.TrimSuffix(ifname, ifTrimSuffix)
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	GoResource struct {
+		Name     string
+		S        string
+		Meta     string
+		Requests []GoServerRequest
+		Events   []GoServerEvent
+	}
+
+	GoServerRequest struct {
+		Name      string
+		IfaceName string
+		PName     string
+		RName     string
+		Fields    []GoArg
+		// Create holds the decoding of the resources the request
+		// creates, which exist whether anybody listens or not
+		Create []string
+		Decode []string
+	}
+
+	GoServerEvent struct {
+		Name      string
+		WlName    string
+		IfaceName string
+		Opcode    int
+		Params    string
+		Convert   []string
+		Args      string
+	}
+)
+
+// resourceType returns the Go type of the resources of iface, as seen
+// from the generated package.
+func resourceType(iface, s string) string {
+	name := wlNames[iface]
+	if strings.HasPrefix(name, "wl.") {
+		return s + name[3:]
+	}
+	return name
+}
+
+func generateServer(protocol *Protocol) {
+	var s string
+	metaPrefix = metaAlias() + "."
+	fmt.Fprintf(fileBuffer, "import (\n\t\"sync\"\n\n\t\"github.com/dkolbly/wl\"\n")
+	if protocol.Name != "wayland" {
+		s = "server."
+		fmt.Fprintf(fileBuffer, "\t\"github.com/dkolbly/wl/server\"\n")
+		fmt.Fprintf(fileBuffer, "\t%s %q\n", metaAlias(), *client)
+	}
+	fmt.Fprintf(fileBuffer, ")\n")
+
+	var resources []GoResource
+	for _, iface := range protocol.Interfaces {
+		r := GoResource{
+			Name: wlNames[iface.Name],
+			S:    s,
+			Meta: qualifyMeta(wlNames[iface.Name]),
+		}
+		r.ProcessRequests(iface)
+		r.ProcessEvents(iface)
+		executeTemplate("ResourceTemplate", resourceTemplate, r)
+		resources = append(resources, r)
+	}
+	executeTemplate("RegisterTemplate", registerTemplate, resources)
+}
+
+// serverType returns the type of an argument of a message of iface on
+// the server side, and the conversion of its wire value to that type.
+func serverType(iface, message string, arg Arg, s string) (typ, conv string) {
+	switch {
+	case (arg.Type == "object" || arg.Type == "new_id") && arg.Interface != "":
+		return "*" + resourceType(arg.Interface, s), ""
+	case arg.Type == "object":
+		return s + "Resource", ""
+	}
+	return goType(iface, message, arg)
+}
+
+func (r *GoResource) ProcessRequests(iface Interface) {
+	for _, wlReq := range iface.Requests {
+		req := GoServerRequest{
+			Name:      CamelCase(wlReq.Name),
+			IfaceName: r.Name,
+			PName:     paramName(wlReq.Name),
+		}
+		req.RName = r.Name + req.Name
+		for _, arg := range wlReq.Args {
+			field := CamelCase(arg.Name)
+			msg := "msg." + field
+			if arg.Type == "new_id" && arg.Interface == "" {
+				req.Fields = append(req.Fields,
+					GoArg{Name: "Interface", Type: "string"},
+					GoArg{Name: "Version", Type: "uint32"},
+					GoArg{Name: field, Type: "uint32"})
+				req.Decode = append(req.Decode,
+					"msg.Interface = req.String()",
+					"msg.Version = req.Uint32()",
+					msg+" = req.Uint32()")
+				continue
+			}
+			t, conv := serverType(iface.Name, wlReq.Name, arg, r.S)
+			req.Fields = append(req.Fields, GoArg{Name: field, Type: t})
+			switch {
+			case arg.Type == "new_id":
+				req.Create = append(req.Create, fmt.Sprintf("%s = req.NewId(new(%s)).(%s)", msg, t[1:], t))
+			case arg.Type == "object" && arg.Interface != "":
+				req.Decode = append(req.Decode,
+					fmt.Sprintf("%s, _ = req.Object(%q, %t).(%s)", msg, arg.Interface, arg.AllowNull, t))
+			case arg.Type == "object":
+				req.Decode = append(req.Decode, fmt.Sprintf("%s = req.Object(\"\", %t)", msg, arg.AllowNull))
+			case conv == "":
+				req.Decode = append(req.Decode, fmt.Sprintf("%s = req.%s", msg, bufTypesMap[arg.Type]))
+			case conv == "[]":
+				req.Decode = append(req.Decode,
+					"for _, v := range wl.Uint32s(req.Array()) {",
+					fmt.Sprintf("\t%s = append(%s, %s(v))", msg, msg, t[2:]),
+					"}")
+			default:
+				req.Decode = append(req.Decode, fmt.Sprintf("%s = %s(req.%s)", msg, conv, bufTypesMap[arg.Type]))
+			}
+		}
+		if req.Create != nil {
+			// the decoding must keep the order of the arguments
+			req.Create = append(req.Create, req.Decode...)
+			req.Decode = nil
+		}
+		r.Requests = append(r.Requests, req)
+	}
+}
+
+func (r *GoResource) ProcessEvents(iface Interface) {
+	for opcode, wlEv := range iface.Events {
+		ev := GoServerEvent{
+			Name:      CamelCase(wlEv.Name),
+			WlName:    wlEv.Name,
+			IfaceName: r.Name,
+			Opcode:    opcode,
+		}
+		var params, args []string
+		for _, arg := range wlEv.Args {
+			name := paramName(arg.Name)
+			t, conv := serverType(iface.Name, wlEv.Name, arg, r.S)
+			params = append(params, name+" "+t)
+			switch {
+			case arg.Type == "new_id" || arg.Type == "object" || conv == "":
+				args = append(args, name)
+			case conv == "[]":
+				ev.Convert = append(ev.Convert,
+					fmt.Sprintf("array := make([]uint32, len(%s))", name),
+					fmt.Sprintf("for i, v := range %s {", name),
+					"\tarray[i] = uint32(v)",
+					"}")
+				args = append(args, "wl.Uint32Array(array)")
+			case conv == "wl.Uint32s":
+				args = append(args, fmt.Sprintf("wl.Uint32Array(%s)", name))
+			default:
+				args = append(args, fmt.Sprintf("%s(%s)", wlTypes[arg.Type], name))
+			}
+		}
+		ev.Params = strings.Join(params, ", ")
+		for _, arg := range args {
+			ev.Args += ", " + arg
+		}
+		r.Events = append(r.Events, ev)
+	}
+}
+
+var (
+	resourceTemplate = `
+{{- range .Requests}}
+type {{.RName}}Request struct {
+	{{- range .Fields}}
+	{{.Name}} {{.Type}}
+	{{- end}}
+}
+
+type {{.RName}}Handler interface {
+	Handle{{.RName}}({{.RName}}Request)
+}
+
+func (r *{{.IfaceName}}) Add{{.Name}}Handler(h {{.RName}}Handler) {
+	if h != nil {
+		r.mu.Lock()
+		r.{{.PName}}Handlers = append(r.{{.PName}}Handlers, h)
+		r.mu.Unlock()
+	}
+}
+
+func (r *{{.IfaceName}}) Remove{{.Name}}Handler(h {{.RName}}Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, e := range r.{{.PName}}Handlers {
+		if e == h {
+			r.{{.PName}}Handlers = append(r.{{.PName}}Handlers[:i:i], r.{{.PName}}Handlers[i+1:]...)
+			break
+		}
+	}
+}
+{{end}}
+{{- if .Requests}}
+func (r *{{.Name}}) Dispatch(req *{{.S}}Request) {
+	switch req.Opcode {
+	{{- range $i, $req := .Requests}}
+	case {{$i}}:
+		{{- if .Create}}
+		msg := {{.RName}}Request{}
+		{{- range .Create}}
+		{{.}}
+		{{- end}}
+		if req.Err() != nil {
+			return
+		}
+		r.mu.RLock()
+		handlers := r.{{.PName}}Handlers
+		r.mu.RUnlock()
+		for _, h := range handlers {
+			h.Handle{{.RName}}(msg)
+		}
+		{{- else}}
+		r.mu.RLock()
+		handlers := r.{{.PName}}Handlers
+		r.mu.RUnlock()
+		if len(handlers) > 0 {
+			msg := {{.RName}}Request{}
+			{{- range .Decode}}
+			{{.}}
+			{{- end}}
+			{{- if .Decode}}
+			if req.Err() != nil {
+				return
+			}
+			{{- end}}
+			for _, h := range handlers {
+				h.Handle{{.RName}}(msg)
+			}
+		}
+		{{- end}}
+	{{- end}}
+	}
+}
+{{end}}
+type {{.Name}} struct {
+	{{.S}}BaseResource
+	{{- if .Requests}}
+	mu sync.RWMutex
+	{{- range .Requests}}
+	{{.PName}}Handlers []{{.RName}}Handler
+	{{- end}}
+	{{- end}}
+}
+
+func New{{.Name}}(c *{{.S}}Client) *{{.Name}} {
+	ret := new({{.Name}})
+	c.Register(ret)
+	return ret
+}
+
+func (r *{{.Name}}) Interface() *wl.Interface {
+	return {{.Meta}}Interface
+}
+{{range .Events}}
+// {{.Name}} sends the {{.WlName}} event.
+func (r *{{.IfaceName}}) {{.Name}}({{.Params}}) error {
+	{{- range .Convert}}
+	{{.}}
+	{{- end}}
+	return r.Client().SendEvent(r, {{.Opcode}}{{.Args}})
+}
+{{end}}`
+
+	registerTemplate = `
+func init() {
+	{{- range .}}
+	{{.S}}RegisterInterface({{.Meta}}Interface, func() {{.S}}Resource { return new({{.Name}}) })
+	{{- end}}
+}
+`
+)
@@ -1,9 +1,17 @@
+// Package wl is a client for the wayland protocol, written in pure Go.
+// The proxies of the core protocol in client.go are generated from
+// protocol/wayland.xml by cmd/wl-scanner.
 package wl
 
-//go:generate go run github.com/dkolbly/wl-scanner -source https://cgit.freedesktop.org/wayland/wayland/plain/protocol/wayland.xml -output client.go
+//go:generate go run ./cmd/wl-scanner -source protocol/wayland.xml -output client.go
 
 type ProxyId uint32
 
+// A Dispatcher decodes the events of a proxy and calls its handlers.
+// The generated proxies call the handlers registered when dispatch
+// starts, without holding any lock, so that handlers may add and remove
+// handlers of the same proxy; the changes take effect from the next
+// event on.
 type Dispatcher interface {
 	Dispatch(*Event)
 }
@@ -16,11 +24,15 @@ type Proxy interface {
 	SetContext(c *Context)
 	Id() ProxyId
 	SetId(id ProxyId)
+	Version() uint32
+	SetVersion(version uint32)
+	Interface() *Interface
 }
 
 type BaseProxy struct {
-	id  ProxyId
-	ctx *Context
+	id      ProxyId
+	version uint32
+	ctx     *Context
 }
 
 func (p *BaseProxy) Id() ProxyId {
@@ -31,6 +43,17 @@ func (p *BaseProxy) SetId(id ProxyId) {
 	p.id = id
 }
 
+// Version returns the version of the interface the proxy was bound
+// or created with.  Requests newer than that fail, and newer events are
+// not dispatched.
+func (p *BaseProxy) Version() uint32 {
+	return p.version
+}
+
+func (p *BaseProxy) SetVersion(version uint32) {
+	p.version = version
+}
+
 func (p *BaseProxy) Context() *Context {
 	return p.ctx
 }
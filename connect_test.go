@@ -0,0 +1,105 @@
+package wl
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// listenDisplay listens on a socket at path and answers the roundtrip
+// of the first client.
+func listenDisplay(t *testing.T, path string) {
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.AcceptUnix()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		answerSync(conn)
+		// wait for the client to hang up
+		conn.Read(make([]byte, 1))
+	}()
+}
+
+func roundtrip(t *testing.T, display *Display) {
+	defer display.Context().Close()
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WAYLAND_SOCKET", "")
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv("WAYLAND_DISPLAY", "wayland-test")
+	listenDisplay(t, filepath.Join(dir, "wayland-test"))
+
+	display, err := Connect("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundtrip(t, display)
+}
+
+func TestConnectAbsolutePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wayland-test")
+	t.Setenv("WAYLAND_SOCKET", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("WAYLAND_DISPLAY", path)
+	listenDisplay(t, path)
+
+	display, err := Connect("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundtrip(t, display)
+
+	if _, err := Connect("wayland-test"); err == nil || err.Error() != "XDG_RUNTIME_DIR not set in the environment" {
+		t.Errorf("relative name without XDG_RUNTIME_DIR: %v", err)
+	}
+}
+
+func TestConnectWaylandSocket(t *testing.T) {
+	conn, peer := socketpair(t)
+	defer peer.Close()
+	f, err := conn.File()
+	conn.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	fd, err := syscall.Dup(int(f.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WAYLAND_SOCKET", strconv.Itoa(fd))
+	t.Setenv("WAYLAND_DISPLAY", "/nonexistent")
+	go answerSync(peer)
+
+	display, err := Connect("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundtrip(t, display)
+	if _, ok := os.LookupEnv("WAYLAND_SOCKET"); ok {
+		t.Error("WAYLAND_SOCKET is still set")
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	conn, peer := socketpair(t)
+	defer peer.Close()
+	go answerSync(peer)
+
+	roundtrip(t, NewDisplay(NewContext(conn)))
+}
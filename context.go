@@ -2,90 +2,380 @@ package wl
 
 import (
 	"errors"
-	"io"
+	"fmt"
 	"log"
 	"net"
 	"os"
+	"path/filepath"
+	"strconv"
 	"sync"
+	"syscall"
+
+	"github.com/dkolbly/wl/wlrecord"
 )
 
 func init() {
 	log.SetFlags(0)
 }
 
+const (
+	// displayId is the id of the wl_display singleton, which is
+	// always the first object registered with a Context.
+	displayId ProxyId = 1
+
+	// serverIdStart is the first object id of the range the server
+	// uses for objects it creates itself, such as wl_data_offer.
+	serverIdStart ProxyId = 0xff000000
+)
+
+// ErrProxyDestroyed is returned when sending a request on, or
+// referring to, a proxy that has been destroyed.
+var ErrProxyDestroyed = errors.New("proxy has been destroyed")
+
 type Context struct {
 	mu        sync.RWMutex
 	conn      *net.UnixConn
 	currentId ProxyId
-	objects   map[ProxyId]Proxy
+	freeIds   []ProxyId
+	// objects maps ids to their proxies; a zombie marks an id whose
+	// proxy has been destroyed but which the server has not released
+	// yet with wl_display.delete_id.
+	objects map[ProxyId]Proxy
+
+	// err is the error that ended the connection; done is closed
+	// once it has been set.
+	errMu sync.Mutex
+	err   error
+	done  chan struct{}
+
+	// manual is set when the application reads and dispatches
+	// events itself.  readToken is held by the goroutine currently
+	// reading from conn in that mode.
+	manual    bool
+	readToken chan struct{}
+
+	// wmu guards the outgoing buffer: out holds the encoded requests
+	// and outFds the duplicated fds to send along with them.
+	wmu    sync.Mutex
+	out    []byte
+	outFds []int
+
+	// in is the chunk events are currently read into, holding
+	// unconsumed data from inStart to inEnd; inOob receives the
+	// ancillary data and inFds queues the received fds until the
+	// events they belong to are split off.
+	in      []byte
+	inStart int
+	inEnd   int
+	inOob   []byte
+	inFds   []int
+
+	// queue is the default event queue; queues holds the proxies
+	// assigned to other queues.
+	queue  *EventQueue
+	queues map[Proxy]*EventQueue
+
+	// pending counts the routed events of each proxy that have not
+	// been dispatched yet; deleted holds the proxies whose ids the
+	// server released with wl_display.delete_id while they still
+	// had pending events, which keep their ids until then.
+	pending map[Proxy]int
+	deleted map[Proxy]bool
+
+	// trace receives the protocol trace lines, if tracing is on
+	trace func(line string)
+
+	// rec receives the traffic, if recording is on
+	rec     *wlrecord.Writer
+	recOnce sync.Once
+
+	// onPanic reports the panics of handlers, if they are recovered
+	onPanic func(*HandlerPanic)
+}
+
+func newContext(conn *net.UnixConn) *Context {
+	c := &Context{
+		conn:      conn,
+		objects:   make(map[ProxyId]Proxy),
+		done:      make(chan struct{}),
+		readToken: make(chan struct{}, 1),
+		queues:    make(map[Proxy]*EventQueue),
+		pending:   make(map[Proxy]int),
+		deleted:   make(map[Proxy]bool),
+	}
+	c.queue = c.NewEventQueue()
+	return c
 }
 
 func (ctx *Context) Register(proxy Proxy) {
 	ctx.mu.Lock()
 	defer ctx.mu.Unlock()
-	ctx.currentId += 1
-	proxy.SetId(ctx.currentId)
+	var id ProxyId
+	if n := len(ctx.freeIds); n > 0 {
+		// reuse the most recently released id, like libwayland
+		id = ctx.freeIds[n-1]
+		ctx.freeIds = ctx.freeIds[:n-1]
+	} else {
+		ctx.currentId += 1
+		id = ctx.currentId
+	}
+	proxy.SetId(id)
+	proxy.SetVersion(1)
 	proxy.SetContext(ctx)
-	ctx.objects[ctx.currentId] = proxy
+	ctx.objects[id] = proxy
 }
 
-func (ctx *Context) lookupProxy(id ProxyId) Proxy {
-	ctx.mu.RLock()
-	defer ctx.mu.RUnlock()
+// release takes back the id of a proxy the server never heard of,
+// because the request creating it failed, and the version the request
+// gave it.
+func (ctx *Context) release(proxy Proxy) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	id := proxy.Id()
+	if ctx.objects[id] != proxy || id >= serverIdStart {
+		return
+	}
+	proxy.SetVersion(1)
+	delete(ctx.objects, id)
+	delete(ctx.queues, proxy)
+	if id == ctx.currentId {
+		ctx.currentId--
+	} else {
+		ctx.freeIds = append(ctx.freeIds, id)
+	}
+}
+
+// Unregister marks proxy as destroyed once its destructor request
+// has been sent.  Events still in flight for it are discarded, and
+// its id is recycled when the server acknowledges the destruction
+// with wl_display.delete_id, or right away if it already has.
+func (ctx *Context) Unregister(proxy Proxy) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	id := proxy.Id()
+	if ctx.objects[id] != proxy {
+		return
+	}
+	if ctx.deleted[proxy] {
+		ctx.free(id, proxy)
+		return
+	}
+	delete(ctx.queues, proxy)
+	// the server does not send delete_id for its own objects, whose
+	// zombies stay until the id is reused
+	ctx.objects[id] = &zombie{iface: proxy.Interface()}
+}
+
+// zombie takes the place of a destroyed proxy until the server
+// releases its id, so that events still in flight for it can be
+// discarded along with their file descriptors.
+type zombie struct {
+	BaseProxy
+	iface *Interface
+}
+
+func (z *zombie) Interface() *Interface {
+	return z.iface
+}
+
+// deleteId handles wl_display.delete_id, which the server sends once
+// it no longer uses an id, either because the client destroyed the
+// object or because the server did so itself (e.g. wl_callback).  The
+// events the server sent before still reach a live proxy: like
+// libwayland, its id is only marked deleted while some of them wait
+// in a queue, and recycled once they have been dispatched.
+func (ctx *Context) deleteId(id ProxyId) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
 	proxy, ok := ctx.objects[id]
 	if !ok {
-		return nil
+		return
+	}
+	if ctx.pending[proxy] > 0 {
+		ctx.deleted[proxy] = true
+		return
+	}
+	ctx.free(id, proxy)
+}
+
+// dispatched accounts for the dispatch of an event routed to proxy,
+// and frees the id of the proxy if the server deleted it and this was
+// its last pending event.
+func (ctx *Context) dispatched(proxy Proxy) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	n := ctx.pending[proxy]
+	if n == 0 {
+		return
 	}
+	if n > 1 {
+		ctx.pending[proxy] = n - 1
+		return
+	}
+	delete(ctx.pending, proxy)
+	if ctx.deleted[proxy] {
+		ctx.free(proxy.Id(), proxy)
+	}
+}
+
+// free forgets proxy, registered under the id the server has
+// released, and recycles the id if it is a client one.  The caller
+// holds mu.
+func (ctx *Context) free(id ProxyId, proxy Proxy) {
+	delete(ctx.queues, proxy)
+	delete(ctx.deleted, proxy)
+	if ctx.objects[id] != proxy {
+		return
+	}
+	delete(ctx.objects, id)
+	if id < serverIdStart {
+		ctx.freeIds = append(ctx.freeIds, id)
+	}
+}
+
+// alive reports whether proxy is still registered and not destroyed.
+func (ctx *Context) alive(proxy Proxy) bool {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	return ctx.objects[proxy.Id()] == proxy
+}
+
+// RegisterAt registers a proxy for an object the server created under
+// the given id, as announced by a new_id argument of an event.
+func (ctx *Context) RegisterAt(proxy Proxy, id ProxyId) error {
+	if id < serverIdStart {
+		return fmt.Errorf("object id %d is not in the server id range", id)
+	}
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	proxy.SetId(id)
+	proxy.SetVersion(1)
+	proxy.SetContext(ctx)
+	ctx.objects[id] = proxy
+	return nil
+}
+
+func (ctx *Context) lookupProxy(id ProxyId) Proxy {
+	proxy, _ := ctx.lookupObject(id)
 	return proxy
 }
 
+// lookupObject returns the proxy registered under id.  A known id
+// with a nil proxy belongs to an object that has been destroyed.
+func (ctx *Context) lookupObject(id ProxyId) (proxy Proxy, known bool) {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	proxy, known = ctx.objects[id]
+	if _, ok := proxy.(*zombie); ok {
+		return nil, true
+	}
+	return proxy, known
+}
+
 func (c *Context) Close() {
+	c.Flush()
 	c.conn.CloseWrite()
 }
 
-func Connect(addr string) (ret *Display, err error) {
-	runtime_dir := os.Getenv("XDG_RUNTIME_DIR")
-	if runtime_dir == "" {
-		return nil, errors.New("XDG_RUNTIME_DIR not set in the environment")
-	}
-	if addr == "" {
-		addr = os.Getenv("WAYLAND_DISPLAY")
-	}
-	if addr == "" {
-		addr = "wayland-0"
+// Done returns a channel that is closed when the connection to the
+// server has ended, after which Err reports why.
+func (c *Context) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns nil while the connection is usable, and the error that
+// ended it afterwards.  A connection closed by the server, including
+// in response to Close, ends with io.EOF.
+func (c *Context) Err() error {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	return c.err
+}
+
+// fail ends the connection with err, unless it already ended.
+func (c *Context) fail(err error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	if c.err != nil {
+		return
 	}
-	addr = runtime_dir + "/" + addr
-	c := new(Context)
-	c.objects = make(map[ProxyId]Proxy)
-	c.currentId = 0
-	c.conn, err = net.DialUnix("unix", nil, &net.UnixAddr{Name: addr, Net: "unix"})
+	c.err = err
+	close(c.done)
+	c.conn.Close()
+}
+
+// Connect connects to a Wayland display the way libwayland does: to
+// the socket passed down in WAYLAND_SOCKET by the compositor that
+// started the program, if any, or else to the display addr, or the one
+// named by WAYLAND_DISPLAY if addr is empty, or wayland-0.  A relative
+// display name is looked up in XDG_RUNTIME_DIR.  Unless the
+// ManualDispatch option is given, events are dispatched on a separate
+// goroutine.
+func Connect(addr string, opts ...Option) (ret *Display, err error) {
+	conn, err := dial(addr)
 	if err != nil {
 		return nil, err
 	}
-	//dispatch events in separate gorutine
-	go c.run()
-	return NewDisplay(c), nil
+	return NewDisplay(NewContext(conn, opts...)), nil
 }
 
-func (c *Context) run() {
-	for {
-		ev, err := c.readEvent()
+// NewContext returns a Context speaking the Wayland protocol over
+// conn, which the Context closes when it is done.  The wl_display
+// proxy must be created with NewDisplay before any other.  Unless the
+// ManualDispatch option is given, events are dispatched on a separate
+// goroutine.
+func NewContext(conn *net.UnixConn, opts ...Option) *Context {
+	c := newContext(conn)
+	if debug := os.Getenv("WAYLAND_DEBUG"); debug == "1" || debug == "client" {
+		Trace(os.Stderr)(c)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if !c.manual {
+		//dispatch events in separate gorutine
+		go c.run()
+	}
+	return c
+}
+
+// dial opens the connection for Connect.
+func dial(addr string) (*net.UnixConn, error) {
+	if s := os.Getenv("WAYLAND_SOCKET"); s != "" {
+		// the socket is good for one connection only, and must not
+		// leak into child processes
+		os.Unsetenv("WAYLAND_SOCKET")
+		fd, err := strconv.Atoi(s)
+		if err != nil || fd < 0 {
+			return nil, fmt.Errorf("invalid WAYLAND_SOCKET %q", s)
+		}
+		syscall.CloseOnExec(fd)
+		f := os.NewFile(uintptr(fd), "WAYLAND_SOCKET")
+		conn, err := net.FileConn(f)
+		f.Close()
 		if err != nil {
-			if err == io.EOF {
-				return
-			}
-			log.Fatal(err)
+			return nil, fmt.Errorf("WAYLAND_SOCKET: %s", err)
 		}
+		uc, ok := conn.(*net.UnixConn)
+		if !ok {
+			conn.Close()
+			return nil, errors.New("WAYLAND_SOCKET is not a unix socket")
+		}
+		return uc, nil
+	}
 
-		proxy := c.lookupProxy(ev.pid)
-		if proxy != nil {
-			if dispatcher, ok := proxy.(Dispatcher); ok {
-				dispatcher.Dispatch(ev)
-			} else {
-				log.Print("Not dispatched")
-			}
-		} else {
-			log.Print("Proxy NULL")
+	if addr == "" {
+		addr = os.Getenv("WAYLAND_DISPLAY")
+	}
+	if addr == "" {
+		addr = "wayland-0"
+	}
+	if !filepath.IsAbs(addr) {
+		runtime_dir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtime_dir == "" {
+			return nil, errors.New("XDG_RUNTIME_DIR not set in the environment")
 		}
+		addr = filepath.Join(runtime_dir, addr)
 	}
+	return net.DialUnix("unix", nil, &net.UnixAddr{Name: addr, Net: "unix"})
 }
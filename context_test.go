@@ -0,0 +1,271 @@
+package wl
+
+import (
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func socketpair(t testing.TB) (*net.UnixConn, *net.UnixConn) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := make([]*net.UnixConn, 2)
+	for i, fd := range fds {
+		f := os.NewFile(uintptr(fd), "socketpair")
+		c, err := net.FileConn(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns[i] = c.(*net.UnixConn)
+	}
+	return conns[0], conns[1]
+}
+
+// connect returns a Context with its wl_display, which dispatches
+// events on its own goroutine unless ManualDispatch is among opts, and
+// the other end of its connection, where the test plays the compositor.
+func connect(t *testing.T, opts ...Option) (*Context, *Display, *net.UnixConn) {
+	conn, peer := socketpair(t)
+	c := newContext(conn)
+	for _, opt := range opts {
+		opt(c)
+	}
+	display := NewDisplay(c)
+	if !c.manual {
+		go c.run()
+	}
+	t.Cleanup(func() {
+		c.Close()
+		peer.Close()
+	})
+	return c, display, peer
+}
+
+// newTestContext returns a Context without a dispatch goroutine whose
+// requests are written to a socket nobody reads.
+func newTestContext(t *testing.T) *Context {
+	conn, peer := socketpair(t)
+	t.Cleanup(func() {
+		conn.Close()
+		peer.Close()
+	})
+	return newContext(conn)
+}
+
+func uint32Data(values ...uint32) []byte {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		Order.PutUint32(data[4*i:], v)
+	}
+	return data
+}
+
+type offerRecorder struct {
+	offers     []*DataOffer
+	selections []*DataOffer
+}
+
+func (r *offerRecorder) HandleDataDeviceDataOffer(ev DataDeviceDataOfferEvent) {
+	r.offers = append(r.offers, ev.Id)
+}
+
+func (r *offerRecorder) HandleDataDeviceSelection(ev DataDeviceSelectionEvent) {
+	r.selections = append(r.selections, ev.Id)
+}
+
+func TestServerCreatedObject(t *testing.T) {
+	c := newTestContext(t)
+	dev := NewDataDevice(c)
+	rec := new(offerRecorder)
+	dev.AddDataOfferHandler(rec)
+	dev.AddSelectionHandler(rec)
+
+	dev.Dispatch(&Event{pid: dev.Id(), Opcode: 0, data: uint32Data(0xff000001)})
+	if len(rec.offers) != 1 || rec.offers[0] == nil {
+		t.Fatalf("data_offer not delivered: %v", rec.offers)
+	}
+	offer := rec.offers[0]
+	if offer.Id() != 0xff000001 || offer.Context() != c {
+		t.Errorf("offer registered as %d", offer.Id())
+	}
+	if p := c.lookupProxy(0xff000001); p != offer {
+		t.Errorf("lookup of server id returned %v", p)
+	}
+
+	dev.Dispatch(&Event{pid: dev.Id(), Opcode: 5, data: uint32Data(0xff000001)})
+	dev.Dispatch(&Event{pid: dev.Id(), Opcode: 5, data: uint32Data(0)})
+	if len(rec.selections) != 2 || rec.selections[0] != offer || rec.selections[1] != nil {
+		t.Errorf("unexpected selections %v", rec.selections)
+	}
+}
+
+func TestRegisterAtRejectsClientIds(t *testing.T) {
+	c := newTestContext(t)
+	if err := c.RegisterAt(new(DataOffer), 5); err == nil {
+		t.Error("expected an error for a client-side id")
+	}
+}
+
+func TestDeleteIdRecyclesIds(t *testing.T) {
+	c := newTestContext(t)
+	NewDisplay(c)
+	cb := NewCallback(c)
+	region := NewRegion(c)
+	if cb.Id() != 2 || region.Id() != 3 {
+		t.Fatalf("unexpected ids %d, %d", cb.Id(), region.Id())
+	}
+
+	// destroying a proxy keeps its id reserved until delete_id
+	if err := region.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if _, known := c.lookupObject(3); !known {
+		t.Error("destroyed id released before delete_id")
+	}
+	if err := region.Add(0, 0, 1, 1); err != ErrProxyDestroyed {
+		t.Errorf("request on destroyed proxy returned %v", err)
+	}
+
+	c.deleteId(3)
+	c.deleteId(2)
+	if _, known := c.lookupObject(2); known {
+		t.Error("callback id still registered after delete_id")
+	}
+	if c.alive(cb) {
+		t.Error("callback still alive after delete_id")
+	}
+
+	if p := NewSurface(c); p.Id() != 2 {
+		t.Errorf("expected id 2 to be reused, got %d", p.Id())
+	}
+	if p := NewSurface(c); p.Id() != 3 {
+		t.Errorf("expected id 3 to be reused, got %d", p.Id())
+	}
+	if p := NewSurface(c); p.Id() != 4 {
+		t.Errorf("expected fresh id 4, got %d", p.Id())
+	}
+}
+
+func TestConnectionFailure(t *testing.T) {
+	conn, peer := socketpair(t)
+	c := newContext(conn)
+	display := NewDisplay(c)
+	go c.run()
+
+	peer.Close()
+	<-c.Done()
+	if err := c.Err(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	if _, err := display.Sync(); err != io.EOF {
+		t.Errorf("request after failure returned %v", err)
+	}
+}
+
+func TestTruncatedEvent(t *testing.T) {
+	c, _, peer := connect(t)
+	registry := NewRegistry(c)
+	ran := false
+	registry.OnGlobal(func(RegistryGlobalEvent) {
+		ran = true
+	})
+
+	// wl_registry.global(1, "wl_seat", 7) cut off after the string length
+	peer.Write(uint32Data(uint32(registry.Id()), 16<<16|0, 1, 8))
+	<-c.Done()
+	if err := c.Err(); err == nil || !strings.Contains(err.Error(), "message too short") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if ran {
+		t.Error("handler ran with a truncated event")
+	}
+}
+
+func TestQueueRequestDupFailure(t *testing.T) {
+	c := newTestContext(t)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var req Request
+	req.PutFd(w.Fd())
+	req.PutFd(^uintptr(0))
+	if err := c.queueRequest(req); err == nil {
+		t.Fatal("queued a request with an invalid fd")
+	}
+	if len(c.out) != 0 || len(c.outFds) != 0 {
+		t.Errorf("failed request left %d bytes and fds %v", len(c.out), c.outFds)
+	}
+	// with the duplicate of the first fd closed, closing w is the
+	// end of the pipe
+	w.Close()
+	r.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := r.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("duplicate of the first fd leaked: %v", err)
+	}
+}
+
+func TestRequestsAreBuffered(t *testing.T) {
+	conn, peer := socketpair(t)
+	defer peer.Close()
+	c := newContext(conn)
+	NewDisplay(c)
+	defer c.Close()
+
+	shm := NewShm(c)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := shm.CreatePool(w.Fd(), 4096); err != nil {
+		t.Fatal(err)
+	}
+	// the request owns a duplicate of the fd
+	w.Close()
+	region := NewRegion(c)
+	if err := region.Add(1, 2, 3, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 64)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := peer.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// create_pool is 16 bytes, region.add 24
+	if n != 40 {
+		t.Fatalf("expected both requests in one write, read %d bytes", n)
+	}
+	if id := Order.Uint32(buf[16:]); id != uint32(region.Id()) {
+		t.Errorf("second request is for object %d", id)
+	}
+	scms, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(scms) != 1 {
+		t.Fatalf("expected one control message, got %v, %v", scms, err)
+	}
+	fds, err := syscall.ParseUnixRights(&scms[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("expected one fd, got %v, %v", fds, err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "pool")
+	defer f.Close()
+	if _, err := f.Write([]byte("x")); err != nil {
+		t.Errorf("received fd is not the pipe: %v", err)
+	}
+	if _, err := r.Read(buf[:1]); err != nil || buf[0] != 'x' {
+		t.Errorf("pipe did not receive the write: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package wl
+
+import (
+	"log"
+)
+
+// An Option configures a Context created by Connect.
+type Option func(*Context)
+
+// ManualDispatch disables the goroutine that normally reads and
+// dispatches events.  The application instead waits for FD to become
+// readable in its own event loop, then calls ReadEvents followed by
+// DispatchPending, so that handlers run on its own goroutine.
+func ManualDispatch() Option {
+	return func(c *Context) {
+		c.manual = true
+	}
+}
+
+// FD returns the file descriptor of the connection to the server, for
+// use in a poll loop together with ManualDispatch.  The descriptor
+// remains owned by the Context.
+func (c *Context) FD() uintptr {
+	var fd uintptr
+	if rc, err := c.conn.SyscallConn(); err == nil {
+		rc.Control(func(s uintptr) {
+			fd = s
+		})
+	}
+	return fd
+}
+
+// ReadEvents reads events from the connection and adds them to the
+// queues of their proxies, blocking until at least one event is
+// available.  All the complete events read so far are queued, since
+// the FD does not become readable again for data already read.  Any
+// read error ends the connection.
+func (c *Context) ReadEvents() error {
+	c.readToken <- struct{}{}
+	defer func() { <-c.readToken }()
+	if err := c.readEvents(); err != nil {
+		c.fail(err)
+		return err
+	}
+	return nil
+}
+
+func (c *Context) readEvents() error {
+	ev, err := c.readEvent()
+	for ev != nil && err == nil {
+		var q *EventQueue
+		if q, err = c.route(ev); err != nil {
+			break
+		}
+		if q != nil {
+			q.push(ev)
+		}
+		ev, err = c.nextEvent()
+	}
+	return err
+}
+
+// DispatchPending dispatches the events in the default queue, see
+// EventQueue.DispatchPending.
+func (c *Context) DispatchPending() (int, error) {
+	return c.queue.DispatchPending()
+}
+
+func (c *Context) run() {
+	for {
+		ev, err := c.readEvent()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		q, err := c.route(ev)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		if q != c.queue {
+			if q != nil {
+				q.push(ev)
+			}
+			continue
+		}
+		c.dispatch(ev)
+		// send whatever the handlers requested
+		c.Flush()
+	}
+}
+
+func (c *Context) dispatch(ev *Event) {
+	defer ev.closeFds()
+	if ev.proxy != nil {
+		defer c.dispatched(ev.proxy)
+	}
+
+	var perr error
+	if ev.pid == displayId {
+		switch {
+		case ev.Opcode == 0:
+			// the handlers see the error before the connection
+			// ends with it
+			perr = c.protocolError(ev)
+		case ev.Opcode == 1 && len(ev.data) >= 4:
+			c.deleteId(ProxyId(Order.Uint32(ev.data)))
+		}
+	}
+
+	proxy, known := ev.proxy, true
+	if proxy == nil {
+		proxy, known = c.lookupObject(ev.pid)
+	} else if !c.alive(proxy) {
+		// destroyed while the event was waiting in its queue
+		if c.trace != nil {
+			c.traceEvent(proxy, ev, true)
+		}
+		return
+	}
+	// events newer than the proxy cannot be decoded reliably
+	tooNew := proxy != nil && ev.Opcode < uint32(len(proxy.Interface().Events)) &&
+		proxy.Interface().Events[ev.Opcode].Since > proxy.Version()
+	if c.trace != nil && (proxy != nil || !known) {
+		c.traceEvent(proxy, ev, tooNew)
+	}
+	if tooNew {
+		return
+	}
+	ev.proxy = proxy
+	if proxy != nil {
+		if dispatcher, ok := proxy.(Dispatcher); ok {
+			c.dispatchTo(dispatcher, ev)
+		} else {
+			log.Print("Not dispatched")
+		}
+	} else if !known {
+		log.Print("Proxy NULL")
+	}
+	if ev.err != nil {
+		c.fail(ev.err)
+	}
+	if perr != nil {
+		c.fail(perr)
+	}
+}
+
+// dispatchTo hands ev to its proxy.  With RecoverPanics, a panic while
+// decoding the event, outside of the handlers, is recovered as well.
+func (c *Context) dispatchTo(dispatcher Dispatcher, ev *Event) {
+	if c.onPanic != nil {
+		defer c.recoverPanic(ev)
+	}
+	dispatcher.Dispatch(ev)
+}
@@ -0,0 +1,157 @@
+package wl
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+type globalRecorder []RegistryGlobalEvent
+
+func (r *globalRecorder) HandleRegistryGlobal(ev RegistryGlobalEvent) {
+	*r = append(*r, ev)
+}
+
+func TestManualDispatch(t *testing.T) {
+	c, display, peer := connect(t, ManualDispatch())
+
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	globals := new(globalRecorder)
+	registry.AddGlobalHandler(globals)
+
+	// wl_registry.global(1, "wl_seat", 7)
+	msg := uint32Data(uint32(registry.Id()), 28<<16|0, 1, 8)
+	msg = append(msg, "wl_seat\x00"...)
+	msg = append(msg, uint32Data(7)...)
+	if _, err := peer.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ReadEvents(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*globals) != 0 {
+		t.Fatal("handler ran before DispatchPending")
+	}
+	n, err := c.DispatchPending()
+	if err != nil || n != 1 {
+		t.Fatalf("DispatchPending returned %d, %v", n, err)
+	}
+	if len(*globals) != 1 || (*globals)[0].Interface != "wl_seat" || (*globals)[0].Version != 7 {
+		t.Errorf("unexpected globals %v", *globals)
+	}
+}
+
+func TestReadEventsBatch(t *testing.T) {
+	c, _, peer := connect(t, ManualDispatch())
+	cbs := []*Callback{NewCallback(c), NewCallback(c), NewCallback(c)}
+	var got []uint32
+	var msg []byte
+	for i, cb := range cbs {
+		cb.OnDone(func(ev CallbackDoneEvent) {
+			got = append(got, ev.CallbackData)
+		})
+		msg = append(msg, uint32Data(uint32(cb.Id()), 12<<16|0, uint32(i))...)
+	}
+	// all three events arrive in a single read
+	if _, err := peer.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ReadEvents(); err != nil {
+		t.Fatal(err)
+	}
+	n, err := c.DispatchPending()
+	if err != nil || n != 3 {
+		t.Fatalf("DispatchPending returned %d, %v", n, err)
+	}
+	if len(got) != 3 || got[0] != 0 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("dispatched %v", got)
+	}
+}
+
+func TestManualRoundtrip(t *testing.T) {
+	c, display, peer := connect(t, ManualDispatch())
+
+	go func() {
+		req := make([]byte, 12)
+		if _, err := io.ReadFull(peer, req); err != nil {
+			return
+		}
+		id := Order.Uint32(req[8:])
+		peer.Write(uint32Data(id, 12<<16|0, 1))
+	}()
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// nobody answers this one
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := display.Roundtrip(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("cancelled roundtrip ended the connection: %v", err)
+	}
+}
+
+func TestReentrantDispatch(t *testing.T) {
+	cb := NewCallback(newTestContext(t))
+	var calls []string
+	var cancelFirst func()
+	cancelFirst = cb.OnDone(func(CallbackDoneEvent) {
+		calls = append(calls, "first")
+		cancelFirst()
+		cb.OnDone(func(CallbackDoneEvent) {
+			calls = append(calls, "added")
+		})
+	})
+	cb.OnDone(func(CallbackDoneEvent) {
+		calls = append(calls, "second")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		cb.Dispatch(doneEvent(cb, 1))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatch deadlocked")
+	}
+	cb.Dispatch(doneEvent(cb, 2))
+	want := []string{"first", "second", "second", "added"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls %v, expected %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls %v, expected %v", calls, want)
+		}
+	}
+}
+
+func TestConcurrentHandlers(t *testing.T) {
+	cb := NewCallback(newTestContext(t))
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			cb.OnDone(func(CallbackDoneEvent) {})()
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		cb.Dispatch(doneEvent(cb, uint32(i)))
+	}
+}
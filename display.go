@@ -0,0 +1,50 @@
+package wl
+
+import (
+	"context"
+)
+
+// Roundtrip blocks until the server has processed every request sent
+// so far and all events it sent in response have been dispatched.
+// It returns early with ctx.Err() if ctx is done first, or with the
+// connection error if the connection ends.  With ManualDispatch, the
+// events are read and dispatched on the calling goroutine.
+func (p *Display) Roundtrip(ctx context.Context) error {
+	return p.RoundtripQueue(ctx, nil)
+}
+
+// RoundtripQueue is like Roundtrip, but dispatches the events of q on
+// the calling goroutine while it waits, so that a library can wait
+// for its own proxies without running the application's handlers.  A
+// nil q stands for the default queue.
+func (p *Display) RoundtripQueue(ctx context.Context, q *EventQueue) error {
+	c := p.Context()
+	if q == nil {
+		q = c.queue
+	}
+	done := make(chan struct{})
+
+	// the handler must be in place before the sync request goes
+	// out, otherwise the done event might be dispatched without it
+	cb := NewCallback(c)
+	defer cb.OnDone(func(CallbackDoneEvent) { close(done) })()
+	c.SetQueue(cb, q)
+	if err := c.SendRequest(p, 0, Proxy(cb)); err != nil {
+		return err
+	}
+
+	if q == c.queue && !c.manual {
+		if err := c.Flush(); err != nil {
+			return err
+		}
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.Done():
+			return c.Err()
+		}
+	}
+	return q.dispatchUntil(ctx, done)
+}
@@ -0,0 +1,47 @@
+package wl
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// answerSync answers the wl_display.sync request of a roundtrip with
+// wl_callback.done.
+func answerSync(peer io.ReadWriter) {
+	req := make([]byte, 12)
+	if _, err := io.ReadFull(peer, req); err != nil {
+		return
+	}
+	id := Order.Uint32(req[8:])
+	msg := uint32Data(id, 12<<16|0, 42)
+	msg = append(msg, uint32Data(uint32(displayId), 12<<16|1, id)...)
+	peer.Write(msg)
+}
+
+func TestRoundtrip(t *testing.T) {
+	_, display, peer := connect(t)
+
+	go answerSync(peer)
+
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRoundtripCancel(t *testing.T) {
+	c, display, peer := connect(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := display.Roundtrip(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+
+	peer.Close()
+	<-c.Done()
+	if err := display.Roundtrip(context.Background()); err == nil || err != c.Err() {
+		t.Errorf("expected the connection error %v, got %v", c.Err(), err)
+	}
+}
@@ -0,0 +1,54 @@
+package wl
+
+import "fmt"
+
+// ProtocolError is a fatal error reported by the server with
+// wl_display.error.  The connection ends with it: Context.Err returns
+// it, as do the calls that fail because of it.
+type ProtocolError struct {
+	// Interface is the interface of the object the error is about,
+	// or empty if the object is unknown.
+	Interface string
+	ObjectId  ProxyId
+	Code      uint32
+	// Name is the symbolic name of the code from the error enum of
+	// the interface, such as "xdg_wm_base.defunct_surfaces", or empty
+	// if the code is not in the protocol.
+	Name    string
+	Message string
+}
+
+func (e *ProtocolError) Error() string {
+	iface := e.Interface
+	if iface == "" {
+		iface = "[unknown]"
+	}
+	code := fmt.Sprint(e.Code)
+	if e.Name != "" {
+		code = fmt.Sprintf("%s (%d)", e.Name, e.Code)
+	}
+	return fmt.Sprintf("protocol error on %s@%d: %s: %s", iface, e.ObjectId, code, e.Message)
+}
+
+// protocolError decodes a wl_display.error event.
+func (c *Context) protocolError(ev *Event) *ProtocolError {
+	r := traceReader{data: ev.data}
+	e := &ProtocolError{ObjectId: ProxyId(r.uint32())}
+	e.Code = r.uint32()
+	e.Message, _ = r.string()
+
+	c.mu.RLock()
+	proxy := c.objects[e.ObjectId]
+	c.mu.RUnlock()
+	if proxy != nil {
+		// destroyed objects keep their interface
+		iface := proxy.Interface()
+		if iface != nil {
+			e.Interface = iface.Name
+		}
+		if name := iface.Enum("error").entry(e.Code); name != "" {
+			e.Name = iface.Name + "." + name
+		}
+	}
+	return e
+}
@@ -0,0 +1,29 @@
+package wl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProtocolError(t *testing.T) {
+	c, display, peer := connect(t)
+
+	msg := "unknown request"
+	data := uint32Data(uint32(displayId), 0, uint32(displayId), uint32(DisplayErrorInvalidMethod), uint32(len(msg)+1))
+	data = append(data, msg...)
+	data = append(data, 0)
+	Order.PutUint32(data[4:], uint32(len(data))<<16|0)
+	peer.Write(data)
+	<-c.Done()
+
+	var perr *ProtocolError
+	if !errors.As(c.Err(), &perr) {
+		t.Fatalf("unexpected error %v", c.Err())
+	}
+	if perr.Name != "wl_display.invalid_method" || perr.ObjectId != display.Id() || perr.Message != msg {
+		t.Errorf("unexpected error %+v", perr)
+	}
+	if s := perr.Error(); s != "protocol error on wl_display@1: wl_display.invalid_method (1): unknown request" {
+		t.Errorf("unexpected message %q", s)
+	}
+}
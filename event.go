@@ -5,83 +5,208 @@ import (
 	"fmt"
 	"io"
 	"syscall"
+
+	"github.com/dkolbly/wl/wlrecord"
 )
 
 type Event struct {
 	pid    ProxyId
 	Opcode uint32
 	data   []byte
-	scms   []syscall.SocketControlMessage
 	off    int
+	// fds holds the file descriptors of the event not yet decoded
+	fds []int
+	// proxy is the target of the event, resolved when the event
+	// was queued
+	proxy Proxy
+	// created holds the proxies of the new_id arguments, registered
+	// when the event was queued and not handed out by NewId yet
+	created []Proxy
+	// err records a decoding failure, which ends the connection
+	err error
 }
 
+const (
+	// readBufferSize is the size of the chunks events are read
+	// into; a chunk is shared by all the events it holds.
+	readBufferSize = 16384
+
+	// maxFdsIn bounds the number of fds received by a single read.
+	maxFdsIn = 28
+)
+
+// readEvent returns the next event from the connection, reading a new
+// chunk of data only when the current one holds no complete message.
 func (c *Context) readEvent() (*Event, error) {
-	buf := make([]byte, 8)
-	control := make([]byte, 24)
+	for {
+		ev, err := c.nextEvent()
+		if ev != nil || err != nil {
+			return ev, err
+		}
+		if err := c.fill(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// nextEvent splits the next complete message off the read buffer, or
+// returns nil if there is none yet.
+func (c *Context) nextEvent() (*Event, error) {
+	buf := c.in[c.inStart:c.inEnd]
+	if len(buf) < 8 {
+		return nil, nil
+	}
+	word := Order.Uint32(buf[4:8])
+	size := int(word >> 16)
+	if size < 8 || size&0x3 != 0 {
+		return nil, fmt.Errorf("invalid message size %d", size)
+	}
+	if len(buf) < size {
+		return nil, nil
+	}
 
-	n, oobn, _, _, err := c.conn.ReadMsgUnix(buf[:], control)
+	ev := &Event{
+		pid:    ProxyId(Order.Uint32(buf[0:4])),
+		Opcode: word & 0xffff,
+		// the slice keeps the chunk alive; it is never written
+		// again once the read position has moved past it
+		data: buf[8:size:size],
+	}
+	c.inStart += size
+	return ev, nil
+}
+
+// fill reads more data, together with any fds, into the read buffer.
+// When the current chunk is full, the unconsumed part moves to a new
+// chunk, since events still refer to the old one.
+func (c *Context) fill() error {
+	need := readBufferSize / 4
+	if c.inEnd-c.inStart >= 8 {
+		// make sure the pending message fits
+		need = int(Order.Uint32(c.in[c.inStart+4:])>>16) - (c.inEnd - c.inStart)
+	}
+	if len(c.in)-c.inEnd < need {
+		size := readBufferSize
+		if c.inEnd-c.inStart+need > size {
+			size = c.inEnd - c.inStart + need
+		}
+		chunk := make([]byte, size)
+		c.inEnd = copy(chunk, c.in[c.inStart:c.inEnd])
+		c.inStart = 0
+		c.in = chunk
+	}
+	if c.inOob == nil {
+		c.inOob = make([]byte, syscall.CmsgSpace(maxFdsIn*4))
+	}
+
+	n, oobn, _, _, err := c.conn.ReadMsgUnix(c.in[c.inEnd:], c.inOob)
+	if err == io.EOF {
+		return err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("reading message header: %v", err)
+		return fmt.Errorf("reading messages: %v", err)
 	}
-	if n != 8 {
-		return nil, fmt.Errorf("unable to read message header")
+	if n == 0 {
+		return io.EOF
 	}
-	ev := new(Event)
+	data := c.in[c.inEnd : c.inEnd+n]
+	c.inEnd += n
+	var fds []int
 	if oobn > 0 {
-		if oobn > len(control) {
-			return nil, fmt.Errorf("unsufficient control msg buffer")
-		}
-		scms, err := syscall.ParseSocketControlMessage(control)
+		scms, err := syscall.ParseSocketControlMessage(c.inOob[:oobn])
 		if err != nil {
-			return nil, fmt.Errorf("control message parse error: %s", err)
+			return fmt.Errorf("control message parse error: %s", err)
+		}
+		for i := range scms {
+			rights, err := syscall.ParseUnixRights(&scms[i])
+			if err != nil {
+				return fmt.Errorf("control message parse error: %s", err)
+			}
+			fds = append(fds, rights...)
 		}
-		ev.scms = scms
+		c.inFds = append(c.inFds, fds...)
 	}
-
-	ev.pid = ProxyId(order.Uint32(buf[0:4]))
-	ev.Opcode = uint32(order.Uint16(buf[4:6]))
-	size := uint32(order.Uint16(buf[6:8]))
-
-	// subtract 8 bytes from header
-	data := make([]byte, int(size)-8)
-	if _, err = io.ReadFull(c.conn, data); err != nil {
-		return nil, fmt.Errorf("reading message body: %v", err)
+	if c.rec != nil {
+		c.record(wlrecord.ServerToClient, data, fds)
 	}
-	ev.data = data
+	return nil
+}
 
-	return ev, nil
+// takeFds moves the file descriptors of ev, which is described by msg,
+// from the connection's fd queue to the event.  The fds of a message
+// are always sent along with or before its data, so they must all have
+// arrived by now.
+func (c *Context) takeFds(ev *Event, msg *Message) error {
+	n := msg.fdCount()
+	if n == 0 {
+		return nil
+	}
+	if n > len(c.inFds) {
+		return fmt.Errorf("%s@%d.%s: missing file descriptor",
+			ev.proxy.Interface().Name, ev.pid, msg.Name)
+	}
+	ev.fds = append([]int(nil), c.inFds[:n]...)
+	c.inFds = c.inFds[n:]
+	return nil
 }
 
+// FD returns the next file descriptor argument of the event, which
+// then belongs to the caller.
 func (ev *Event) FD() uintptr {
-	if ev.scms == nil {
-		return 0
+	if len(ev.fds) == 0 {
+		ev.err = fmt.Errorf("object %d event %d: missing file descriptor", ev.pid, ev.Opcode)
+		return ^uintptr(0)
 	}
-	fds, err := syscall.ParseUnixRights(&ev.scms[0])
-	if err != nil {
-		panic("Unable to parse unix rights")
+	fd := ev.fds[0]
+	ev.fds = ev.fds[1:]
+	return uintptr(fd)
+}
+
+// closeFds closes the file descriptors nobody decoded, e.g. because
+// there was no handler for the event.
+func (ev *Event) closeFds() {
+	for _, fd := range ev.fds {
+		syscall.Close(fd)
 	}
-	//TODO is this required
-	ev.scms = append(ev.scms, ev.scms[1:]...)
-	return uintptr(fds[0])
+	ev.fds = nil
 }
 
 func (ev *Event) Uint32() uint32 {
 	buf := ev.next(4)
-	if len(buf) != 4 {
-		panic("Unable to read unsigned int")
+	if buf == nil {
+		return 0
 	}
-	return order.Uint32(buf)
+	return Order.Uint32(buf)
 }
 
 func (ev *Event) Proxy(c *Context) Proxy {
 	return c.lookupProxy(ProxyId(ev.Uint32()))
 }
 
+// NewId reads the id of an object created by the server and returns
+// its proxy, which was registered when the event was queued.  Events
+// that did not go through a queue register proxy under the id instead.
+// The new object has the version of the one the event was sent to.
+func (ev *Event) NewId(c *Context, proxy Proxy) Proxy {
+	if len(ev.created) > 0 {
+		ev.Uint32()
+		proxy, ev.created = ev.created[0], ev.created[1:]
+		return proxy
+	}
+	if err := c.RegisterAt(proxy, ProxyId(ev.Uint32())); err != nil && ev.err == nil {
+		ev.err = err
+	}
+	if ev.proxy != nil {
+		proxy.SetVersion(ev.proxy.Version())
+	}
+	return proxy
+}
+
 func (ev *Event) String() string {
 	l := int(ev.Uint32())
 	buf := ev.next(l)
-	if len(buf) != l {
-		panic("Unable to read string")
+	if buf == nil {
+		return ""
 	}
 	ret := string(bytes.TrimRight(buf, "\x00"))
 	//padding to 32 bit boundary
@@ -95,20 +220,36 @@ func (ev *Event) Int32() int32 {
 	return int32(ev.Uint32())
 }
 
-func (ev *Event) Float32() float32 {
-	return float32(fixedToFloat64(ev.Int32()))
+func (ev *Event) Fixed() Fixed {
+	return Fixed(ev.Uint32())
 }
 
-func (ev *Event) Array() []int32 {
+// Array returns the bytes of an array argument.  Arrays of uint32,
+// the most common kind, are decoded with Uint32s.
+func (ev *Event) Array() []byte {
 	l := int(ev.Uint32())
-	arr := make([]int32, l/4)
-	for i := range arr {
-		arr[i] = ev.Int32()
+	buf := ev.next(l)
+	if buf == nil {
+		return nil
 	}
-	return arr
+	//padding to 32 bit boundary
+	if (l & 0x3) != 0 {
+		ev.next(4 - (l & 0x3))
+	}
+	return append([]byte(nil), buf...)
 }
 
+// next returns the following n bytes of the event.  If the event is
+// too short, it records the error and returns nil, and the values
+// decoded from then on are zero.
 func (ev *Event) next(n int) []byte {
+	if ev.err != nil {
+		return nil
+	}
+	if n > len(ev.data)-ev.off {
+		ev.err = fmt.Errorf("object %d event %d: message too short", ev.pid, ev.Opcode)
+		return nil
+	}
 	ret := ev.data[ev.off : ev.off+n]
 	ev.off += n
 	return ret
@@ -0,0 +1,200 @@
+package wl
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestReadEventSplitsChunks(t *testing.T) {
+	conn, peer := socketpair(t)
+	defer peer.Close()
+	c := newContext(conn)
+	defer conn.Close()
+
+	// enough small messages to cross chunk boundaries, and one that
+	// is larger than a chunk
+	var stream []byte
+	const count = 3000
+	for i := 0; i < count; i++ {
+		stream = append(stream, uint32Data(uint32(i), 16<<16|uint32(i%7), uint32(i), ^uint32(i))...)
+	}
+	big := bytes.Repeat([]byte{0xab}, 3*readBufferSize)
+	stream = append(stream, uint32Data(7, uint32(len(big)+8)<<16|3)...)
+	stream = append(stream, big...)
+	go peer.Write(stream)
+
+	for i := 0; i < count; i++ {
+		ev, err := c.readEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.pid != ProxyId(i) || ev.Opcode != uint32(i%7) {
+			t.Fatalf("event %d: got object %d opcode %d", i, ev.pid, ev.Opcode)
+		}
+		if a, b := ev.Uint32(), ev.Uint32(); a != uint32(i) || b != ^uint32(i) {
+			t.Fatalf("event %d: got arguments %d, %d", i, a, b)
+		}
+	}
+	ev, err := c.readEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.pid != 7 || ev.Opcode != 3 || !bytes.Equal(ev.data, big) {
+		t.Errorf("large message corrupted: object %d opcode %d, %d bytes", ev.pid, ev.Opcode, len(ev.data))
+	}
+}
+
+type keymapRecorder struct {
+	fds []uintptr
+}
+
+func (r *keymapRecorder) HandleKeyboardKeymap(ev KeyboardKeymapEvent) {
+	r.fds = append(r.fds, ev.Fd)
+}
+
+func TestEventFdQueue(t *testing.T) {
+	conn, peer := socketpair(t)
+	defer peer.Close()
+	c := newContext(conn)
+	defer conn.Close()
+	NewDisplay(c)
+	kbd := NewKeyboard(c)
+	pointer := NewPointer(c)
+	rec := new(keymapRecorder)
+	kbd.AddKeymapHandler(rec)
+
+	// both fds arrive with the first message; an unrelated event sits
+	// between the two keymaps
+	r1, w1, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r1.Close()
+	defer w1.Close()
+	r2, w2, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r2.Close()
+	defer w2.Close()
+	var msgs []byte
+	msgs = append(msgs, uint32Data(uint32(kbd.Id()), 16<<16|0, 1, 10)...)
+	msgs = append(msgs, uint32Data(uint32(pointer.Id()), 20<<16|2, 0, 1, 1)...)
+	msgs = append(msgs, uint32Data(uint32(kbd.Id()), 16<<16|0, 1, 20)...)
+	rights := syscall.UnixRights(int(w1.Fd()), int(w2.Fd()))
+	if _, _, err := peer.WriteMsgUnix(msgs, rights, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.readEvents(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DispatchPending(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.fds) != 2 {
+		t.Fatalf("expected two keymaps, got %v", rec.fds)
+	}
+	for i, r := range []*os.File{r1, r2} {
+		f := os.NewFile(rec.fds[i], "keymap")
+		if _, err := f.Write([]byte{byte(i)}); err != nil {
+			t.Errorf("keymap %d: %v", i, err)
+		}
+		f.Close()
+		buf := make([]byte, 1)
+		if _, err := r.Read(buf); err != nil || buf[0] != byte(i) {
+			t.Errorf("keymap %d received the wrong fd", i)
+		}
+	}
+}
+
+func TestEventMissingFd(t *testing.T) {
+	conn, peer := socketpair(t)
+	defer peer.Close()
+	c := newContext(conn)
+	defer conn.Close()
+	NewDisplay(c)
+	kbd := NewKeyboard(c)
+
+	peer.Write(uint32Data(uint32(kbd.Id()), 16<<16|0, 1, 10))
+	err := c.ReadEvents()
+	if err == nil || !strings.Contains(err.Error(), "wl_keyboard@2.keymap: missing file descriptor") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if c.Err() != err {
+		t.Errorf("connection not failed: %v", c.Err())
+	}
+}
+
+func BenchmarkReadEvent(b *testing.B) {
+	conn, peer := socketpair(b)
+	defer conn.Close()
+	defer peer.Close()
+	c := newContext(conn)
+
+	// a burst of wl_pointer.motion events
+	var burst []byte
+	for i := 0; i < 100; i++ {
+		burst = append(burst, uint32Data(3, 20<<16|2, uint32(i), 256, 512)...)
+	}
+	go func() {
+		for n := 0; n < b.N; n += 100 {
+			if _, err := peer.Write(burst); err != nil {
+				return
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.readEvent(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEventArray(t *testing.T) {
+	data := uint32Data(5)
+	data = append(data, "hello\x00\x00\x00"...)
+	data = append(data, uint32Data(8, 1, 2, 42)...)
+	ev := &Event{data: data}
+	if a := ev.Array(); string(a) != "hello" {
+		t.Errorf("got array %q", a)
+	}
+	if keys := Uint32s(ev.Array()); len(keys) != 2 || keys[0] != 1 || keys[1] != 2 {
+		t.Errorf("got keys %v", keys)
+	}
+	if v := ev.Uint32(); v != 42 {
+		t.Errorf("the argument after the arrays is %d", v)
+	}
+}
+
+func TestKeyboardEnterKeys(t *testing.T) {
+	c := newTestContext(t)
+	kbd := NewKeyboard(c)
+	var keys []uint32
+	kbd.OnEnter(func(ev KeyboardEnterEvent) {
+		keys = ev.Keys
+	})
+	kbd.Dispatch(&Event{pid: kbd.Id(), Opcode: 1, data: uint32Data(7, 0, 8, 30, 48)})
+	if len(keys) != 2 || keys[0] != 30 || keys[1] != 48 {
+		t.Errorf("got keys %v", keys)
+	}
+}
+
+func TestPutArray(t *testing.T) {
+	var r Request
+	r.PutArray([]byte("hello"))
+	r.PutArray(Uint32Array([]uint32{1, 2}))
+	r.PutArray(nil)
+	want := uint32Data(5)
+	want = append(want, "hello\x00\x00\x00"...)
+	want = append(want, uint32Data(8, 1, 2, 0)...)
+	if !bytes.Equal(r.data, want) {
+		t.Errorf("encoded % x, expected % x", r.data, want)
+	}
+}
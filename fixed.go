@@ -0,0 +1,33 @@
+package wl
+
+import "strconv"
+
+// Fixed is the wl_fixed_t of the protocol: a signed 24.8 fixed point
+// number, ranging from -8388608 to 8388607.99609375 in steps of 1/256.
+// Every Fixed converts exactly to a float64.
+type Fixed int32
+
+// FixedFromFloat64 returns the Fixed closest to v.  Values out of range
+// wrap around, as they do in libwayland.
+func FixedFromFloat64(v float64) Fixed {
+	return Fixed(float64ToFixed(v))
+}
+
+// FixedFromInt returns v as a Fixed.  Values out of range wrap around.
+func FixedFromInt(v int) Fixed {
+	return Fixed(v << 8)
+}
+
+func (f Fixed) Float64() float64 {
+	return fixedToFloat64(int32(f))
+}
+
+// Int returns f without its fractional part, rounding toward zero like
+// wl_fixed_to_int.
+func (f Fixed) Int() int {
+	return int(f / 256)
+}
+
+func (f Fixed) String() string {
+	return strconv.FormatFloat(f.Float64(), 'f', -1, 64)
+}
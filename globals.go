@@ -0,0 +1,202 @@
+package wl
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A Global is an object the server advertises through wl_registry.
+type Global struct {
+	Name      uint32
+	Interface string
+	Version   uint32
+}
+
+// Globals keeps the list of globals announced to a registry up to
+// date, and binds them by type.  Create it right after GetRegistry, and
+// do a roundtrip to learn the globals the server starts with:
+//
+//	globals := wl.NewGlobals(registry)
+//	display.Roundtrip(ctx)
+//	compositor, err := wl.BindGlobal(globals, wl.NewCompositor, 4)
+type Globals struct {
+	registry *Registry
+
+	mu       sync.Mutex
+	globals  []Global
+	watchers []*globalWatcher
+}
+
+// globalWatcher is told about the globals of an interface coming and
+// going.
+type globalWatcher struct {
+	iface   string
+	added   func(Global)
+	removed func(Global)
+}
+
+// NewGlobals starts tracking the globals of registry.
+func NewGlobals(registry *Registry) *Globals {
+	g := &Globals{registry: registry}
+	registry.AddGlobalHandler(g)
+	registry.AddGlobalRemoveHandler(g)
+	return g
+}
+
+func (g *Globals) Registry() *Registry {
+	return g.registry
+}
+
+// List returns the globals currently advertised, in the order they
+// were announced.
+func (g *Globals) List() []Global {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Global(nil), g.globals...)
+}
+
+// Lookup returns the first global advertised for an interface.
+func (g *Globals) Lookup(iface string) (Global, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, global := range g.globals {
+		if global.Interface == iface {
+			return global, true
+		}
+	}
+	return Global{}, false
+}
+
+func (g *Globals) HandleRegistryGlobal(ev RegistryGlobalEvent) {
+	global := Global{Name: ev.Name, Interface: ev.Interface, Version: ev.Version}
+	g.mu.Lock()
+	g.globals = append(g.globals, global)
+	watchers := g.watching(global.Interface)
+	g.mu.Unlock()
+
+	for _, w := range watchers {
+		w.added(global)
+	}
+}
+
+func (g *Globals) HandleRegistryGlobalRemove(ev RegistryGlobalRemoveEvent) {
+	g.mu.Lock()
+	var global Global
+	found := false
+	for i, other := range g.globals {
+		if other.Name == ev.Name {
+			global, found = other, true
+			g.globals = append(g.globals[:i], g.globals[i+1:]...)
+			break
+		}
+	}
+	var watchers []*globalWatcher
+	if found {
+		watchers = g.watching(global.Interface)
+	}
+	g.mu.Unlock()
+
+	for _, w := range watchers {
+		w.removed(global)
+	}
+}
+
+// watching returns the watchers of an interface.  g.mu must be held.
+func (g *Globals) watching(iface string) []*globalWatcher {
+	var watchers []*globalWatcher
+	for _, w := range g.watchers {
+		if w.iface == iface {
+			watchers = append(watchers, w)
+		}
+	}
+	return watchers
+}
+
+// interfaceOf returns the interface of the proxies of type T, which
+// the generated Interface methods return without looking at their
+// receiver.
+func interfaceOf[T Proxy]() *Interface {
+	var zero T
+	return zero.Interface()
+}
+
+// BindGlobal binds the first global of the interface of T to a proxy
+// created with newProxy, at the advertised version or at version,
+// whichever is lower.  It returns a nil proxy if there is no such
+// global or binding fails.
+func BindGlobal[T Proxy](g *Globals, newProxy func(*Context) T, version uint32) (T, error) {
+	var none T
+	iface := interfaceOf[T]()
+	global, ok := g.Lookup(iface.Name)
+	if !ok {
+		return none, fmt.Errorf("no %s global", iface.Name)
+	}
+	proxy, err := bindGlobal(g, global, newProxy, version)
+	if err != nil {
+		return none, err
+	}
+	return proxy, nil
+}
+
+func bindGlobal[T Proxy](g *Globals, global Global, newProxy func(*Context) T, version uint32) (T, error) {
+	if global.Version < version {
+		version = global.Version
+	}
+	proxy := newProxy(g.registry.Context())
+	return proxy, g.registry.Bind(global.Name, global.Interface, version, proxy)
+}
+
+// WatchGlobals binds every global of the interface of T like
+// BindGlobal, both those already advertised and those announced later,
+// such as hotplugged outputs and seats, and calls added with each
+// proxy, or with a nil proxy and the error if binding fails.  When one
+// of the globals is withdrawn, removed is called with its proxy, which
+// the caller should then release.  The callbacks run on the goroutine
+// dispatching the registry events, except for the globals already
+// known, which are bound before WatchGlobals returns.
+func WatchGlobals[T Proxy](g *Globals, newProxy func(*Context) T, version uint32, added func(T, Global, error), removed func(T, Global)) {
+	var mu sync.Mutex
+	bound := make(map[uint32]T)
+	w := &globalWatcher{
+		iface: interfaceOf[T]().Name,
+		added: func(global Global) {
+			proxy, err := bindGlobal(g, global, newProxy, version)
+			if err != nil {
+				var none T
+				if added != nil {
+					added(none, global, err)
+				}
+				return
+			}
+			mu.Lock()
+			bound[global.Name] = proxy
+			mu.Unlock()
+			if added != nil {
+				added(proxy, global, nil)
+			}
+		},
+		removed: func(global Global) {
+			mu.Lock()
+			proxy, ok := bound[global.Name]
+			delete(bound, global.Name)
+			mu.Unlock()
+			if ok && removed != nil {
+				removed(proxy, global)
+			}
+		},
+	}
+
+	g.mu.Lock()
+	g.watchers = append(g.watchers, w)
+	var known []Global
+	for _, global := range g.globals {
+		if global.Interface == w.iface {
+			known = append(known, global)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, global := range known {
+		w.added(global)
+	}
+}
@@ -0,0 +1,103 @@
+package wl
+
+import "testing"
+
+func newTestGlobals(t *testing.T) *Globals {
+	c := newTestContext(t)
+	registry, err := NewDisplay(c).GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGlobals(registry)
+	g.HandleRegistryGlobal(RegistryGlobalEvent{Name: 1, Interface: "wl_compositor", Version: 6})
+	g.HandleRegistryGlobal(RegistryGlobalEvent{Name: 2, Interface: "wl_seat", Version: 3})
+	return g
+}
+
+func TestBindGlobal(t *testing.T) {
+	g := newTestGlobals(t)
+
+	compositor, err := BindGlobal(g, NewCompositor, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compositor.Version() != 4 {
+		t.Errorf("compositor bound at version %d", compositor.Version())
+	}
+	seat, err := BindGlobal(g, NewSeat, SeatInterface.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seat.Version() != 3 {
+		t.Errorf("seat bound at version %d", seat.Version())
+	}
+
+	output, err := BindGlobal(g, NewOutput, 2)
+	if err == nil || output != nil {
+		t.Errorf("bound a missing global: %v, %v", output, err)
+	}
+}
+
+func TestGlobalsList(t *testing.T) {
+	g := newTestGlobals(t)
+	g.HandleRegistryGlobalRemove(RegistryGlobalRemoveEvent{Name: 1})
+	list := g.List()
+	if len(list) != 1 || list[0] != (Global{Name: 2, Interface: "wl_seat", Version: 3}) {
+		t.Errorf("unexpected globals %v", list)
+	}
+	if _, ok := g.Lookup("wl_compositor"); ok {
+		t.Error("the removed global is still there")
+	}
+}
+
+func TestWatchGlobals(t *testing.T) {
+	g := newTestGlobals(t)
+	g.HandleRegistryGlobal(RegistryGlobalEvent{Name: 3, Interface: "wl_output", Version: 2})
+
+	outputs := make(map[uint32]*Output)
+	WatchGlobals(g, NewOutput, 3,
+		func(output *Output, global Global, err error) {
+			if err != nil {
+				t.Errorf("binding %v: %s", global, err)
+			}
+			outputs[global.Name] = output
+		},
+		func(output *Output, global Global) {
+			if outputs[global.Name] != output {
+				t.Errorf("removed %v, which was not added", global)
+			}
+			delete(outputs, global.Name)
+		})
+	if len(outputs) != 1 || outputs[3].Version() != 2 {
+		t.Fatalf("unexpected outputs %v", outputs)
+	}
+
+	// hotplug
+	g.HandleRegistryGlobal(RegistryGlobalEvent{Name: 4, Interface: "wl_output", Version: 4})
+	if len(outputs) != 2 || outputs[4].Version() != 3 {
+		t.Fatalf("unexpected outputs %v", outputs)
+	}
+	g.HandleRegistryGlobalRemove(RegistryGlobalRemoveEvent{Name: 3})
+	g.HandleRegistryGlobalRemove(RegistryGlobalRemoveEvent{Name: 2})
+	if len(outputs) != 1 || outputs[4] == nil {
+		t.Errorf("unexpected outputs %v", outputs)
+	}
+}
+
+func TestWatchGlobalsBindError(t *testing.T) {
+	g := newTestGlobals(t)
+	var errs []error
+	WatchGlobals(g, NewOutput, 3,
+		func(output *Output, global Global, err error) {
+			if output != nil {
+				t.Errorf("got proxy %v for a failed bind", output)
+			}
+			errs = append(errs, err)
+		}, nil)
+
+	g.Registry().Context().Unregister(g.Registry())
+	g.HandleRegistryGlobal(RegistryGlobalEvent{Name: 3, Interface: "wl_output", Version: 2})
+	if len(errs) != 1 || errs[0] != ErrProxyDestroyed {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
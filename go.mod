@@ -1,9 +1,8 @@
 module github.com/dkolbly/wl
 
-go 1.12
+go 1.18
 
 require (
-	github.com/dkolbly/wl-scanner v0.0.0-20180219202019-635e0a21c642
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	golang.org/x/image v0.0.0-20190501045829-6d32002ffd75
 )
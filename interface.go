@@ -0,0 +1,166 @@
+package wl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ArgType is the wire type of a request or event argument, using the
+// letters of libwayland's message signatures.
+type ArgType byte
+
+const (
+	ArgInt    ArgType = 'i'
+	ArgUint   ArgType = 'u'
+	ArgFixed  ArgType = 'f'
+	ArgString ArgType = 's'
+	ArgObject ArgType = 'o'
+	ArgNewId  ArgType = 'n'
+	ArgArray  ArgType = 'a'
+	ArgFd     ArgType = 'h'
+)
+
+// Arg describes an argument of a request or event.
+type Arg struct {
+	Name string
+	Type ArgType
+	// Interface is the interface of an object or new_id argument,
+	// or empty if any interface is allowed (as in wl_registry.bind)
+	Interface string
+	AllowNull bool
+}
+
+// Message describes a request or event of an interface.
+type Message struct {
+	Name  string
+	Since uint32
+	// Destructor is set for requests that destroy their object
+	Destructor bool
+	Args       []Arg
+}
+
+// fdCount returns the number of file descriptors sent with the message.
+func (m *Message) fdCount() int {
+	n := 0
+	for _, arg := range m.Args {
+		if arg.Type == ArgFd {
+			n++
+		}
+	}
+	return n
+}
+
+// EnumEntry is a named value of an enum.
+type EnumEntry struct {
+	Name  string
+	Value uint32
+}
+
+// Enum describes an enum of an interface.  The values of a bitfield
+// are flags to be combined.
+type Enum struct {
+	Name     string
+	Bitfield bool
+	Entries  []EnumEntry
+}
+
+// entry returns the name of a value, or "" if it has none.
+func (e *Enum) entry(value uint32) string {
+	if e == nil {
+		return ""
+	}
+	for _, entry := range e.Entries {
+		if entry.Value == value {
+			return entry.Name
+		}
+	}
+	return ""
+}
+
+// Format returns the name of value, or for a bitfield the names of its
+// flags joined by "|".  Values without a name are formatted as numbers.
+func (e *Enum) Format(value uint32) string {
+	if name := e.entry(value); name != "" {
+		return name
+	}
+	if e == nil || !e.Bitfield || value == 0 {
+		return strconv.FormatUint(uint64(value), 10)
+	}
+	var names []string
+	for _, entry := range e.Entries {
+		if entry.Value != 0 && value&entry.Value == entry.Value {
+			names = append(names, entry.Name)
+			value &^= entry.Value
+		}
+	}
+	if value != 0 {
+		names = append(names, fmt.Sprintf("%#x", value))
+	}
+	return strings.Join(names, "|")
+}
+
+// Interface describes a protocol interface.  The generated bindings
+// provide one for each interface, indexed by opcode.
+type Interface struct {
+	Name     string
+	Version  uint32
+	Requests []Message
+	Events   []Message
+	Enums    []Enum
+}
+
+// constructors maps interface names to functions returning a new
+// proxy of the interface, for the objects the server creates with the
+// new_id arguments of events, and interfaces maps them to their
+// descriptions.
+var (
+	constructors = make(map[string]func() Proxy)
+	interfaces   = make(map[string]*Interface)
+)
+
+// RegisterInterface makes the proxy type returned by newProxy known for
+// the interface, so that objects of it created by the server get their
+// proxy as soon as the event announcing them is read.  The generated
+// bindings register their types.
+func RegisterInterface(iface *Interface, newProxy func() Proxy) {
+	constructors[iface.Name] = newProxy
+	interfaces[iface.Name] = iface
+}
+
+// LookupInterface returns the description of the interface with the
+// given name, or nil if no bindings linked into the program registered
+// it.
+func LookupInterface(name string) *Interface {
+	return interfaces[name]
+}
+
+// Enum returns the description of the enum with the given name, or
+// nil if the interface has none.
+func (i *Interface) Enum(name string) *Enum {
+	if i == nil {
+		return nil
+	}
+	for k := range i.Enums {
+		if i.Enums[k].Name == name {
+			return &i.Enums[k]
+		}
+	}
+	return nil
+}
+
+// event returns the description of the event with the given opcode.
+func (i *Interface) event(opcode uint32) *Message {
+	if i == nil || opcode >= uint32(len(i.Events)) {
+		return nil
+	}
+	return &i.Events[opcode]
+}
+
+// request returns the description of the request with the given opcode.
+func (i *Interface) request(opcode uint32) *Message {
+	if i == nil || opcode >= uint32(len(i.Requests)) {
+		return nil
+	}
+	return &i.Requests[opcode]
+}
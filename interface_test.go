@@ -0,0 +1,41 @@
+package wl
+
+import "testing"
+
+func TestEnumString(t *testing.T) {
+	for _, test := range []struct {
+		v    interface{ String() string }
+		want string
+	}{
+		{ShmFormatArgb8888, "argb8888"},
+		{OutputTransformFlipped90, "flipped_90"},
+		{OutputTransform(9), "9"},
+		{SeatCapabilityPointer | SeatCapabilityKeyboard, "pointer|keyboard"},
+		{SeatCapabilityTouch | 16, "touch|0x10"},
+		{SeatCapability(0), "0"},
+		{DataDeviceManagerDndActionNone, "none"},
+	} {
+		if got := test.v.String(); got != test.want {
+			t.Errorf("got %q, expected %q", got, test.want)
+		}
+	}
+}
+
+func TestEnumHas(t *testing.T) {
+	caps := SeatCapabilityPointer | SeatCapabilityTouch
+	if !caps.Has(SeatCapabilityPointer) || !caps.Has(SeatCapabilityPointer|SeatCapabilityTouch) {
+		t.Error("flags that are set are missing")
+	}
+	if caps.Has(SeatCapabilityKeyboard) || caps.Has(SeatCapabilityPointer|SeatCapabilityKeyboard) {
+		t.Error("flags that are not set are present")
+	}
+}
+
+func TestLookupInterface(t *testing.T) {
+	if iface := LookupInterface("wl_data_offer"); iface != DataOfferInterface {
+		t.Errorf("wl_data_offer is %v", iface)
+	}
+	if iface := LookupInterface("wl_nonexistent"); iface != nil {
+		t.Errorf("unknown interface is %v", iface)
+	}
+}
@@ -0,0 +1,209 @@
+// Package wire encodes and decodes Wayland messages for the server
+// side packages.
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/dkolbly/wl"
+)
+
+// MaxFds is the number of file descriptors read or written along with
+// one message, as in libwayland.
+const MaxFds = 28
+
+// ErrShortMessage is returned when a message ends before all of its
+// arguments have been read.
+var ErrShortMessage = errors.New("message too short")
+
+// A Message is a request or event as it travels on the wire, minus
+// its file descriptors.
+type Message struct {
+	Id     uint32
+	Opcode uint32
+	Data   []byte
+}
+
+// A Reader splits the data read from a connection into messages, and
+// queues the file descriptors received along with them.
+type Reader struct {
+	conn *net.UnixConn
+	in   []byte
+	fds  []int
+}
+
+func NewReader(conn *net.UnixConn) *Reader {
+	return &Reader{conn: conn}
+}
+
+// ReadMessage returns the next complete message, reading from the
+// connection as needed.  It returns io.EOF when the peer has closed the
+// connection.
+func (r *Reader) ReadMessage() (*Message, error) {
+	for {
+		if len(r.in) >= 8 {
+			word := wl.Order.Uint32(r.in[4:])
+			size := int(word >> 16)
+			if size < 8 || size%4 != 0 {
+				return nil, fmt.Errorf("invalid message size %d", size)
+			}
+			if len(r.in) >= size {
+				msg := &Message{
+					Id:     wl.Order.Uint32(r.in),
+					Opcode: word & 0xffff,
+					Data:   append([]byte(nil), r.in[8:size]...),
+				}
+				r.in = r.in[size:]
+				return msg, nil
+			}
+		}
+
+		buf := make([]byte, 4096)
+		oob := make([]byte, syscall.CmsgSpace(MaxFds*4))
+		n, oobn, _, _, err := r.conn.ReadMsgUnix(buf, oob)
+		if n == 0 && err == nil {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.in = append(r.in, buf[:n]...)
+		if oobn > 0 {
+			scms, err := syscall.ParseSocketControlMessage(oob[:oobn])
+			if err != nil {
+				return nil, err
+			}
+			for i := range scms {
+				fds, err := syscall.ParseUnixRights(&scms[i])
+				if err != nil {
+					return nil, err
+				}
+				r.fds = append(r.fds, fds...)
+			}
+		}
+	}
+}
+
+// TakeFd removes the oldest received file descriptor from the queue.
+func (r *Reader) TakeFd() (int, bool) {
+	if len(r.fds) == 0 {
+		return -1, false
+	}
+	fd := r.fds[0]
+	r.fds = r.fds[1:]
+	return fd, true
+}
+
+// Close closes the file descriptors nobody took.
+func (r *Reader) Close() {
+	for _, fd := range r.fds {
+		syscall.Close(fd)
+	}
+	r.fds = nil
+}
+
+// A Decoder reads the arguments of a message.  After the first error,
+// it returns zero values and keeps the error in Err.
+type Decoder struct {
+	Data []byte
+	Err  error
+}
+
+func (d *Decoder) next(n int) []byte {
+	if d.Err != nil {
+		return nil
+	}
+	if n > len(d.Data) {
+		d.Err = ErrShortMessage
+		return nil
+	}
+	buf := d.Data[:n]
+	d.Data = d.Data[n:]
+	return buf
+}
+
+func (d *Decoder) Uint32() uint32 {
+	buf := d.next(4)
+	if buf == nil {
+		return 0
+	}
+	return wl.Order.Uint32(buf)
+}
+
+func (d *Decoder) Int32() int32 {
+	return int32(d.Uint32())
+}
+
+func (d *Decoder) Fixed() wl.Fixed {
+	return wl.Fixed(d.Uint32())
+}
+
+func (d *Decoder) Array() []byte {
+	n := int(d.Uint32())
+	buf := d.next(n)
+	d.next((4 - n&3) & 3)
+	return buf
+}
+
+func (d *Decoder) String() string {
+	return string(bytes.TrimRight(d.Array(), "\x00"))
+}
+
+// An Encoder builds the arguments of a message.
+type Encoder struct {
+	Data []byte
+	Fds  []int
+}
+
+func (e *Encoder) PutUint32(v uint32) {
+	var buf [4]byte
+	wl.Order.PutUint32(buf[:], v)
+	e.Data = append(e.Data, buf[:]...)
+}
+
+func (e *Encoder) PutInt32(v int32) {
+	e.PutUint32(uint32(v))
+}
+
+func (e *Encoder) PutFixed(v wl.Fixed) {
+	e.PutInt32(int32(v))
+}
+
+func (e *Encoder) PutArray(a []byte) {
+	e.PutUint32(uint32(len(a)))
+	e.Data = append(e.Data, a...)
+	e.Data = append(e.Data, make([]byte, (4-len(a)&3)&3)...)
+}
+
+// PutString appends a string; the empty string is sent as null.
+func (e *Encoder) PutString(s string) {
+	if s == "" {
+		e.PutUint32(0)
+		return
+	}
+	e.PutArray(append([]byte(s), 0))
+}
+
+func (e *Encoder) PutFd(fd int) {
+	e.Fds = append(e.Fds, fd)
+}
+
+// WriteMessage sends the message built by e to id with the given
+// opcode.
+func (e *Encoder) WriteMessage(conn *net.UnixConn, id, opcode uint32) error {
+	msg := make([]byte, 8, 8+len(e.Data))
+	wl.Order.PutUint32(msg, id)
+	wl.Order.PutUint32(msg[4:], uint32(len(e.Data)+8)<<16|opcode)
+	msg = append(msg, e.Data...)
+	var oob []byte
+	if len(e.Fds) > 0 {
+		oob = syscall.UnixRights(e.Fds...)
+	}
+	_, _, err := conn.WriteMsgUnix(msg, oob, nil)
+	return err
+}
@@ -0,0 +1,91 @@
+package wl
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// A HandlerPanic describes a panic recovered from an event handler.
+type HandlerPanic struct {
+	// Interface is the interface of the object the event was sent
+	// to, or empty if the object is unknown.
+	Interface string
+	ObjectId  ProxyId
+	Opcode    uint32
+	// Event is the name of the event, or empty if the opcode is not
+	// in the protocol.
+	Event string
+	// Value is the value passed to panic, and Stack the stack trace of
+	// the handler at that point.
+	Value interface{}
+	Stack []byte
+}
+
+func (p *HandlerPanic) Error() string {
+	iface, event := p.Interface, p.Event
+	if iface == "" {
+		iface = "[unknown]"
+	}
+	if event == "" {
+		event = fmt.Sprintf("[opcode %d]", p.Opcode)
+	}
+	return fmt.Sprintf("panic in handler of %s@%d.%s: %v", iface, p.ObjectId, event, p.Value)
+}
+
+// RecoverPanics makes the Context recover the panics of event
+// handlers, so that a failing handler does not take down the dispatch
+// goroutine and the whole program.  Each handler invocation is
+// recovered separately: the other handlers of the event still run, and
+// dispatch goes on with the next event.  report is called with every
+// panic on the dispatching goroutine; if it is nil, the panics are
+// logged with their stack.
+func RecoverPanics(report func(*HandlerPanic)) Option {
+	if report == nil {
+		report = func(p *HandlerPanic) {
+			log.Printf("wl: %s\n%s", p, p.Stack)
+		}
+	}
+	return func(c *Context) {
+		c.onPanic = report
+	}
+}
+
+// Call runs h, a handler of the event.  The generated Dispatch methods
+// call each handler through it, so that their panics are recovered if
+// the Context was created with RecoverPanics.  Handlers are not called
+// for an event that failed to decode.
+func (ev *Event) Call(h func()) {
+	if ev.err != nil {
+		return
+	}
+	if ev.proxy != nil {
+		if c := ev.proxy.Context(); c != nil && c.onPanic != nil {
+			defer c.recoverPanic(ev)
+		}
+	}
+	h()
+}
+
+// recoverPanic reports a panic while dispatching ev.  It must be
+// deferred, for recover to stop the panic.
+func (c *Context) recoverPanic(ev *Event) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	p := &HandlerPanic{
+		ObjectId: ev.pid,
+		Opcode:   ev.Opcode,
+		Value:    r,
+		Stack:    debug.Stack(),
+	}
+	if ev.proxy != nil {
+		iface := ev.proxy.Interface()
+		p.Interface = iface.Name
+		if msg := iface.event(ev.Opcode); msg != nil {
+			p.Event = msg.Name
+		}
+	}
+	c.onPanic(p)
+}
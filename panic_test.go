@@ -0,0 +1,41 @@
+package wl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	c := newTestContext(t)
+	var panics []*HandlerPanic
+	RecoverPanics(func(p *HandlerPanic) {
+		panics = append(panics, p)
+	})(c)
+	cb := NewCallback(c)
+	var offer *DataOffer
+	cb.OnDone(func(CallbackDoneEvent) {
+		offer.Destroy()
+	})
+	ran := false
+	cb.OnDone(func(CallbackDoneEvent) {
+		ran = true
+	})
+
+	c.dispatch(doneEvent(cb, 1))
+	if !ran {
+		t.Error("the handler after the panicking one did not run")
+	}
+	if len(panics) != 1 {
+		t.Fatalf("reported %d panics", len(panics))
+	}
+	p := panics[0]
+	if p.Interface != "wl_callback" || p.ObjectId != cb.Id() || p.Opcode != 0 || p.Event != "done" {
+		t.Errorf("unexpected report %+v", p)
+	}
+	if !strings.Contains(string(p.Stack), "TestRecoverPanics") {
+		t.Errorf("stack lacks the handler:\n%s", p.Stack)
+	}
+	if !strings.HasPrefix(p.Error(), "panic in handler of wl_callback@1.done: runtime error") {
+		t.Errorf("unexpected message %q", p.Error())
+	}
+}
@@ -0,0 +1,256 @@
+package wl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// An EventQueue holds the events of the proxies assigned to it until
+// they are dispatched by whoever drains the queue, like libwayland's
+// wl_event_queue.  Every Context has a default queue, which the
+// dispatch goroutine drains unless ManualDispatch is in effect.
+type EventQueue struct {
+	ctx    *Context
+	mu     sync.Mutex
+	events []*Event
+	// ready is signalled whenever events are added
+	ready chan struct{}
+}
+
+// NewEventQueue creates an empty event queue.  Assign proxies to it
+// with SetQueue.
+func (c *Context) NewEventQueue() *EventQueue {
+	return &EventQueue{
+		ctx:   c,
+		ready: make(chan struct{}, 1),
+	}
+}
+
+// SetQueue assigns proxy to q, so that its future events are queued
+// there.  A nil q assigns it back to the default queue.
+func (c *Context) SetQueue(proxy Proxy, q *EventQueue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if q == nil || q == c.queue {
+		delete(c.queues, proxy)
+	} else {
+		c.queues[proxy] = q
+	}
+}
+
+// route resolves the target of ev, hands it its file descriptors,
+// registers the objects it creates and returns the queue it belongs
+// to.  Events for destroyed proxies are discarded, and route returns a
+// nil queue for them.
+func (c *Context) route(ev *Event) (*EventQueue, error) {
+	c.mu.RLock()
+	proxy := c.objects[ev.pid]
+	q, ok := c.queues[proxy]
+	c.mu.RUnlock()
+	if !ok {
+		q = c.queue
+	}
+	if proxy == nil {
+		// without its signature, neither the fds nor the objects
+		// of the event can be accounted for
+		return nil, fmt.Errorf("event %d for unknown object %d", ev.Opcode, ev.pid)
+	}
+
+	ev.proxy = proxy
+	msg := proxy.Interface().event(ev.Opcode)
+	if msg == nil {
+		return nil, fmt.Errorf("%s@%d: invalid event opcode %d",
+			proxy.Interface().Name, ev.pid, ev.Opcode)
+	}
+	if err := c.takeFds(ev, msg); err != nil {
+		return nil, err
+	}
+	if err := c.createObjects(ev, msg, q); err != nil {
+		return nil, err
+	}
+	if _, ok := proxy.(*zombie); ok {
+		if c.trace != nil {
+			c.traceEvent(proxy, ev, true)
+		}
+		ev.closeFds()
+		return nil, nil
+	}
+	c.mu.Lock()
+	c.pending[proxy]++
+	c.mu.Unlock()
+	return q, nil
+}
+
+// createObjects registers proxies for the objects created by the
+// new_id arguments of ev, which is described by msg.  The new objects
+// belong to the queue and have the version of the object the event
+// was sent to, and they must exist before the next event is routed,
+// which may well be sent to them.  The objects created by events for
+// destroyed proxies are zombies from the start.
+func (c *Context) createObjects(ev *Event, msg *Message, q *EventQueue) error {
+	_, dead := ev.proxy.(*zombie)
+	dec := Event{pid: ev.pid, Opcode: ev.Opcode, data: ev.data}
+	for _, arg := range msg.Args {
+		switch arg.Type {
+		case ArgString, ArgArray:
+			dec.Array()
+		case ArgFd:
+		case ArgNewId:
+			id := ProxyId(dec.Uint32())
+			if dec.err != nil {
+				// Dispatch reports the truncation
+				return nil
+			}
+			newProxy := constructors[arg.Interface]
+			if newProxy == nil {
+				return fmt.Errorf("%s@%d.%s: no proxy type for interface %q",
+					ev.proxy.Interface().Name, ev.pid, msg.Name, arg.Interface)
+			}
+			proxy := newProxy()
+			if dead {
+				proxy = &zombie{iface: proxy.Interface()}
+			}
+			if err := c.RegisterAt(proxy, id); err != nil {
+				return err
+			}
+			if !dead {
+				proxy.SetVersion(ev.proxy.Version())
+				c.SetQueue(proxy, q)
+				ev.created = append(ev.created, proxy)
+			}
+		default:
+			dec.Uint32()
+		}
+	}
+	return nil
+}
+
+func (q *EventQueue) push(ev *Event) {
+	q.mu.Lock()
+	q.events = append(q.events, ev)
+	q.mu.Unlock()
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (q *EventQueue) pop() *Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.events) == 0 {
+		return nil
+	}
+	ev := q.events[0]
+	q.events[0] = nil
+	q.events = q.events[1:]
+	return ev
+}
+
+// DispatchPending calls the handlers for all events in the queue on
+// the calling goroutine without waiting for more, and returns how
+// many events were dispatched.  Once the connection has ended, it
+// still dispatches what was queued but also returns the connection
+// error.
+func (q *EventQueue) DispatchPending() (int, error) {
+	n := 0
+	for ev := q.pop(); ev != nil; ev = q.pop() {
+		q.ctx.dispatch(ev)
+		n++
+	}
+	return n, q.ctx.Err()
+}
+
+// Dispatch waits until the queue holds at least one event and then
+// dispatches all of them like DispatchPending.  With ManualDispatch,
+// it reads from the connection itself while waiting.
+func (q *EventQueue) Dispatch(ctx context.Context) (int, error) {
+	for {
+		n, err := q.DispatchPending()
+		if n > 0 || err != nil {
+			return n, err
+		}
+		if err := q.wait(ctx); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// dispatchUntil dispatches the events in the queue on the calling
+// goroutine until done is closed, ctx is done or the connection ends.
+func (q *EventQueue) dispatchUntil(ctx context.Context, done <-chan struct{}) error {
+	for {
+		_, err := q.DispatchPending()
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+		if err != nil {
+			return err
+		}
+		if err := q.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// wait flushes the outgoing requests and blocks until events may have
+// been added to the queue.  With ManualDispatch, the calling goroutine
+// reads from the connection unless another one is already reading.
+func (q *EventQueue) wait(ctx context.Context) error {
+	c := q.ctx
+	if err := c.Flush(); err != nil {
+		return err
+	}
+	var token chan struct{}
+	if c.manual {
+		token = c.readToken
+	}
+	select {
+	case <-q.ready:
+		return nil
+	case token <- struct{}{}:
+		defer func() { <-token }()
+		return c.readEventsContext(ctx)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+// readEventsContext is like ReadEvents, except that the read is
+// interrupted when ctx is done, without ending the connection.  The
+// caller must hold the read token.
+func (c *Context) readEventsContext(ctx context.Context) error {
+	if ctx.Done() != nil {
+		var wg sync.WaitGroup
+		stop := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				c.conn.SetReadDeadline(time.Unix(1, 0))
+			case <-stop:
+			}
+		}()
+		defer func() {
+			close(stop)
+			wg.Wait()
+			c.conn.SetReadDeadline(time.Time{})
+		}()
+	}
+
+	if err := c.readEvents(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.fail(err)
+		return err
+	}
+	return nil
+}
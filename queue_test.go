@@ -0,0 +1,199 @@
+package wl
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEventQueue(t *testing.T) {
+	c, display, peer := connect(t)
+
+	q := c.NewEventQueue()
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetQueue(registry, q)
+	globals := new(globalRecorder)
+	registry.AddGlobalHandler(globals)
+
+	go func() {
+		// get_registry, then sync; the global is sent before the
+		// callback fires
+		req := make([]byte, 24)
+		if _, err := io.ReadFull(peer, req); err != nil {
+			return
+		}
+		id := Order.Uint32(req[20:])
+		msg := uint32Data(uint32(registry.Id()), 28<<16|0, 1, 8)
+		msg = append(msg, "wl_seat\x00"...)
+		msg = append(msg, uint32Data(7)...)
+		msg = append(msg, uint32Data(id, 12<<16|0, 1)...)
+		peer.Write(msg)
+	}()
+
+	// the default queue never sees the registry event
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*globals) != 0 {
+		t.Fatal("registry event dispatched outside of its queue")
+	}
+
+	n, err := q.Dispatch(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Dispatch returned %d, %v", n, err)
+	}
+	if len(*globals) != 1 || (*globals)[0].Interface != "wl_seat" {
+		t.Errorf("unexpected globals %v", *globals)
+	}
+}
+
+func TestRoundtripQueueManual(t *testing.T) {
+	c, display, peer := connect(t, ManualDispatch())
+
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultGlobals := new(globalRecorder)
+	registry.AddGlobalHandler(defaultGlobals)
+
+	q := c.NewEventQueue()
+	go func() {
+		req := make([]byte, 24)
+		if _, err := io.ReadFull(peer, req); err != nil {
+			return
+		}
+		id := Order.Uint32(req[20:])
+		msg := uint32Data(uint32(registry.Id()), 28<<16|0, 1, 8)
+		msg = append(msg, "wl_seat\x00"...)
+		msg = append(msg, uint32Data(7)...)
+		msg = append(msg, uint32Data(id, 12<<16|0, 1)...)
+		peer.Write(msg)
+	}()
+
+	if err := display.RoundtripQueue(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	if len(*defaultGlobals) != 0 {
+		t.Fatal("private roundtrip ran handlers of the default queue")
+	}
+	if n, _ := c.DispatchPending(); n != 1 || len(*defaultGlobals) != 1 {
+		t.Errorf("expected the global to wait in the default queue, dispatched %d", n)
+	}
+}
+
+// offerEvents returns wl_data_device.data_offer announcing a new offer
+// on dev, followed by wl_data_offer.offer of text/plain on the offer.
+func offerEvents(dev *DataDevice) []byte {
+	msg := uint32Data(uint32(dev.Id()), 12<<16|0, 0xff000001)
+	msg = append(msg, uint32Data(0xff000001, 24<<16|0, 11)...)
+	return append(msg, "text/plain\x00\x00"...)
+}
+
+func TestNewIdQueue(t *testing.T) {
+	c, display, peer := connect(t)
+	q := c.NewEventQueue()
+	dev := NewDataDevice(c)
+	c.SetQueue(dev, q)
+	var mimeTypes []string
+	dev.OnDataOffer(func(ev DataDeviceDataOfferEvent) {
+		ev.Id.OnOffer(func(ev DataOfferOfferEvent) {
+			mimeTypes = append(mimeTypes, ev.MimeType)
+		})
+	})
+
+	go func() {
+		peer.Write(offerEvents(dev))
+		answerSync(peer)
+	}()
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// the offer event follows its object into the queue of the device
+	n, err := q.DispatchPending()
+	if err != nil || n != 2 {
+		t.Fatalf("DispatchPending returned %d, %v", n, err)
+	}
+	if len(mimeTypes) != 1 || mimeTypes[0] != "text/plain" {
+		t.Errorf("unexpected offers %v", mimeTypes)
+	}
+	if offer := c.lookupProxy(0xff000001); offer == nil || offer.Version() != dev.Version() {
+		t.Errorf("offer registered as %v", offer)
+	}
+}
+
+func TestNewIdDestroyedParent(t *testing.T) {
+	c, display, peer := connect(t)
+	dev := NewDataDevice(c)
+	ran := false
+	dev.OnDataOffer(func(DataDeviceDataOfferEvent) {
+		ran = true
+	})
+	c.Unregister(dev)
+
+	// the events of the offer are discarded along with the one
+	// creating it, instead of being taken for those of an unknown
+	// object
+	go func() {
+		peer.Write(offerEvents(dev))
+		answerSync(peer)
+	}()
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Error("handler of a destroyed device ran")
+	}
+}
+
+func TestDeleteIdPendingEvent(t *testing.T) {
+	c, display, peer := connect(t)
+	q := c.NewEventQueue()
+	cb := NewCallback(c)
+	var data []uint32
+	cb.OnDone(func(ev CallbackDoneEvent) {
+		data = append(data, ev.CallbackData)
+	})
+	c.SetQueue(cb, q)
+	if err := c.SendRequest(display, 0, Proxy(cb)); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		answerSync(peer) // done and delete_id for cb
+		answerSync(peer) // the roundtrip below
+	}()
+	// once the roundtrip is through, the dispatch goroutine has
+	// handled the delete_id while the done event waits in q
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, known := c.lookupObject(cb.Id()); !known {
+		t.Fatal("id released with an event still queued")
+	}
+
+	n, err := q.DispatchPending()
+	if err != nil || n != 1 {
+		t.Fatalf("DispatchPending returned %d, %v", n, err)
+	}
+	if len(data) != 1 || data[0] != 42 {
+		t.Errorf("unexpected done events %v", data)
+	}
+	if _, known := c.lookupObject(cb.Id()); known {
+		t.Error("id not released after the last event")
+	}
+}
+
+func TestEventForUnknownObject(t *testing.T) {
+	c, _, peer := connect(t)
+
+	peer.Write(uint32Data(0xff000005, 12<<16|0, 1))
+	<-c.Done()
+	if err := c.Err(); err == nil || !strings.Contains(err.Error(), "unknown object") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
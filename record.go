@@ -0,0 +1,28 @@
+package wl
+
+import (
+	"io"
+	"log"
+
+	"github.com/dkolbly/wl/wlrecord"
+)
+
+// Record makes the Context write all traffic with the server to w,
+// with a description of the file descriptors passed, so that the
+// session can be replayed with wltest.Replay.  The format is that of
+// package wlrecord.
+func Record(w io.Writer) Option {
+	return func(c *Context) {
+		c.rec = wlrecord.NewWriter(w)
+	}
+}
+
+// record adds a chunk of traffic to the recording.  A broken recording
+// is reported once, but does not affect the connection.
+func (c *Context) record(dir wlrecord.Direction, data []byte, fds []int) {
+	if err := c.rec.Write(dir, data, fds); err != nil {
+		c.recOnce.Do(func() {
+			log.Printf("wl: recording failed: %v", err)
+		})
+	}
+}
@@ -1,16 +1,27 @@
 package wl
 
 import (
-	"errors"
-	"net"
+	"fmt"
 	"syscall"
+
+	"github.com/dkolbly/wl/wlrecord"
+)
+
+const (
+	// maxBufferSize is the amount of request data buffered before
+	// it is flushed to the server, as in libwayland.
+	maxBufferSize = 4096
+
+	// maxFdsOut is the number of file descriptors sent along with
+	// one write, as in libwayland.
+	maxFdsOut = 28
 )
 
 type Request struct {
 	pid    ProxyId
 	opcode uint32
 	data   []byte
-	oob    []byte
+	fds    []uintptr
 }
 
 func (context *Context) SendRequest(proxy Proxy, opcode uint32, args ...interface{}) (err error) {
@@ -19,11 +30,91 @@ func (context *Context) SendRequest(proxy Proxy, opcode uint32, args ...interfac
 		opcode: opcode,
 	}
 
+	msg := proxy.Interface().request(opcode)
+	created := newObjects(msg, proxy.Version(), args)
+	defer func() {
+		if err != nil {
+			// the server never hears of the new objects
+			for _, obj := range created {
+				context.release(obj.proxy)
+			}
+		}
+	}()
+
+	if err := context.Err(); err != nil {
+		return err
+	}
+	if !context.alive(proxy) {
+		return ErrProxyDestroyed
+	}
+	if msg != nil && msg.Since > proxy.Version() {
+		return fmt.Errorf("%s.%s needs version %d, but %s@%d has version %d",
+			proxy.Interface().Name, msg.Name, msg.Since, proxy.Interface().Name, proxy.Id(), proxy.Version())
+	}
+	for _, obj := range created {
+		iface := obj.proxy.Interface()
+		if obj.bound && (obj.version == 0 || obj.version > iface.Version) {
+			return fmt.Errorf("%s version %d is not supported, only 1 to %d", iface.Name, obj.version, iface.Version)
+		}
+	}
 	for _, arg := range args {
+		if p, ok := arg.(Proxy); ok && !context.alive(p) {
+			return ErrProxyDestroyed
+		}
 		req.Write(arg)
 	}
 
-	return writeRequest(context.conn, req)
+	if context.trace != nil {
+		context.traceRequest(proxy, &req)
+	}
+	// the replies to the request may be dispatched as soon as it is
+	// queued, and must find the new objects at their version
+	context.mu.Lock()
+	for _, obj := range created {
+		obj.proxy.SetVersion(obj.version)
+	}
+	context.mu.Unlock()
+	return context.queueRequest(req)
+}
+
+// newObject is an object created by a request, with its version.
+// bound is set for the object of wl_registry.bind, whose version the
+// client picks.
+type newObject struct {
+	proxy   Proxy
+	version uint32
+	bound   bool
+}
+
+// newObjects finds the new_id arguments of a request.  They take the
+// version of the proxy the request is sent to, except for
+// wl_registry.bind, which passes the interface and version of the new
+// object before it.
+func newObjects(msg *Message, version uint32, args []interface{}) []newObject {
+	if msg == nil {
+		return nil
+	}
+	var created []newObject
+	i := 0
+	for _, arg := range msg.Args {
+		if arg.Type == ArgNewId {
+			obj := newObject{version: version}
+			if arg.Interface == "" && i+2 < len(args) {
+				obj.version, _ = args[i+1].(uint32)
+				obj.bound = true
+				i += 2
+			}
+			if i >= len(args) {
+				break
+			}
+			if p, ok := args[i].(Proxy); ok {
+				obj.proxy = p
+				created = append(created, obj)
+			}
+		}
+		i++
+	}
+	return created
 }
 
 func (r *Request) Write(arg interface{}) {
@@ -34,11 +125,11 @@ func (r *Request) Write(arg interface{}) {
 		r.PutUint32(t)
 	case int32:
 		r.PutInt32(t)
-	case float32:
-		r.PutFloat32(t)
+	case Fixed:
+		r.PutFixed(t)
 	case string:
 		r.PutString(t)
-	case []int32:
+	case []byte:
 		r.PutArray(t)
 	case uintptr:
 		r.PutFd(t)
@@ -49,7 +140,7 @@ func (r *Request) Write(arg interface{}) {
 
 func (r *Request) PutUint32(u uint32) {
 	buf := make([]byte, 4)
-	order.PutUint32(buf, u)
+	Order.PutUint32(buf, u)
 	r.data = append(r.data, buf...)
 }
 
@@ -61,9 +152,8 @@ func (r *Request) PutInt32(i int32) {
 	r.PutUint32(uint32(i))
 }
 
-func (r *Request) PutFloat32(f float32) {
-	fx := float64ToFixed(float64(f))
-	r.PutUint32(uint32(fx))
+func (r *Request) PutFixed(f Fixed) {
+	r.PutUint32(uint32(f))
 }
 
 func (r *Request) PutString(s string) {
@@ -77,35 +167,98 @@ func (r *Request) PutString(s string) {
 	}
 }
 
-func (r *Request) PutArray(a []int32) {
+// PutArray appends an array argument holding the bytes of a.  Arrays
+// of uint32 are encoded with Uint32Array.
+func (r *Request) PutArray(a []byte) {
 	r.PutUint32(uint32(len(a)))
-	for _, e := range a {
-		r.PutUint32(uint32(e))
+	r.data = append(r.data, a...)
+	// padding to 32 bit boundary
+	if tail := len(a) & 0x3; tail != 0 {
+		r.data = append(r.data, make([]byte, 4-tail)...)
 	}
 }
 
 func (r *Request) PutFd(fd uintptr) {
-	rights := syscall.UnixRights(int(fd))
-	r.oob = append(r.oob, rights...)
+	r.fds = append(r.fds, fd)
 }
 
-func writeRequest(conn *net.UnixConn, r Request) error {
-	var header []byte
+// queueRequest appends r to the outgoing buffer, flushing the buffer
+// first if r does not fit.  The file descriptors of r are duplicated,
+// so the caller may close its own copies right away.
+func (c *Context) queueRequest(r Request) error {
 	// calculate message total size
 	size := uint32(len(r.data) + 8)
-	buf := make([]byte, 4)
-	order.PutUint32(buf, uint32(r.pid))
-	header = append(header, buf...)
-	order.PutUint32(buf, uint32(size<<16|r.opcode&0x0000ffff))
-	header = append(header, buf...)
+	header := make([]byte, 8)
+	Order.PutUint32(header[0:4], uint32(r.pid))
+	Order.PutUint32(header[4:8], uint32(size<<16|r.opcode&0x0000ffff))
 
-	d, c, err := conn.WriteMsgUnix(append(header, r.data...), r.oob, nil)
-	if err != nil {
-		return err
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	if len(c.out)+int(size) > maxBufferSize || len(c.outFds)+len(r.fds) > maxFdsOut {
+		if err := c.flushLocked(); err != nil {
+			return err
+		}
 	}
-	if c != len(r.oob) || d != (len(header)+len(r.data)) {
-		return errors.New("WriteMsgUnix failed")
+	dups := make([]int, 0, len(r.fds))
+	for _, fd := range r.fds {
+		dup, err := dupCloexec(int(fd))
+		if err != nil {
+			for _, d := range dups {
+				syscall.Close(d)
+			}
+			return fmt.Errorf("duplicating fd %d: %v", fd, err)
+		}
+		dups = append(dups, dup)
 	}
-
+	c.outFds = append(c.outFds, dups...)
+	c.out = append(c.out, header...)
+	c.out = append(c.out, r.data...)
 	return nil
 }
+
+// Flush writes all buffered requests to the server.  Requests are
+// buffered until Flush is called, a roundtrip starts, the buffer fills
+// up, or the dispatch goroutine finishes handling an event; code that
+// sends requests outside of event handlers and does not wait for a
+// reply should call Flush afterwards.
+func (c *Context) Flush() error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	return c.flushLocked()
+}
+
+func (c *Context) flushLocked() error {
+	if len(c.out) == 0 {
+		return nil
+	}
+	if c.rec != nil {
+		c.record(wlrecord.ClientToServer, c.out, c.outFds)
+	}
+	var oob []byte
+	if len(c.outFds) > 0 {
+		oob = syscall.UnixRights(c.outFds...)
+	}
+	n, _, err := c.conn.WriteMsgUnix(c.out, oob, nil)
+	if err == nil && n < len(c.out) {
+		// the fds went out with the first part
+		_, err = c.conn.Write(c.out[n:])
+	}
+	for _, fd := range c.outFds {
+		syscall.Close(fd)
+	}
+	c.out = c.out[:0]
+	c.outFds = c.outFds[:0]
+	if err != nil {
+		c.fail(err)
+	}
+	return err
+}
+
+func dupCloexec(fd int) (int, error) {
+	dup, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(fd), syscall.F_DUPFD_CLOEXEC, 0)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(dup), nil
+}
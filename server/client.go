@@ -0,0 +1,312 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+)
+
+const (
+	displayId uint32 = 1
+
+	// serverIdStart is the first id of objects created by the server
+	serverIdStart uint32 = 0xff000000
+)
+
+// ErrClientGone is returned by SendEvent after the client has been
+// disconnected without an error of its own.
+var ErrClientGone = errors.New("client disconnected")
+
+// A Client is a connection to the server.  Its requests are read and
+// dispatched by a goroutine of its own, so handlers for the requests of
+// one client are never called concurrently.
+type Client struct {
+	srv  *Server
+	conn *net.UnixConn
+	r    *wire.Reader
+	wmu  sync.Mutex
+
+	mu         sync.RWMutex
+	resources  map[uint32]Resource
+	nextId     uint32
+	display    *Display
+	registries []*Registry
+
+	errMu sync.Mutex
+	err   error
+	done  chan struct{}
+}
+
+func newClient(s *Server, conn *net.UnixConn) *Client {
+	c := &Client{
+		srv:       s,
+		conn:      conn,
+		r:         wire.NewReader(conn),
+		resources: make(map[uint32]Resource),
+		nextId:    serverIdStart,
+		done:      make(chan struct{}),
+	}
+	c.display = new(Display)
+	c.registerAt(c.display, displayId, 1)
+	c.display.AddSyncHandler(c)
+	c.display.AddGetRegistryHandler(c)
+	return c
+}
+
+// Server returns the server the client is connected to.
+func (c *Client) Server() *Server {
+	return c.srv
+}
+
+// Register gives res a new server-side id, for a resource the server
+// creates and announces with a new_id event argument.
+func (c *Client) Register(res Resource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := res.base()
+	b.id = c.nextId
+	b.client = c
+	c.nextId++
+	c.resources[b.id] = res
+}
+
+// registerAt registers a resource created by the client.
+func (c *Client) registerAt(res Resource, id, version uint32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id == 0 || id >= serverIdStart || c.resources[id] != nil {
+		return &protocolError{
+			resource: c.display,
+			code:     uint32(wl.DisplayErrorInvalidObject),
+			msg:      fmt.Sprintf("invalid new id %d", id),
+		}
+	}
+	b := res.base()
+	b.id = id
+	b.version = version
+	b.client = c
+	c.resources[id] = res
+	return nil
+}
+
+// Resource returns the resource with the given id, or nil.
+func (c *Client) Resource(id uint32) Resource {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.resources[id]
+}
+
+// Destroy removes a resource.  Resources created by the client are
+// destroyed when it sends a destructor request; others, like the
+// wl_callback of a frame request, are destroyed by the server once they
+// have served their purpose.
+func (c *Client) Destroy(res Resource) {
+	c.mu.Lock()
+	if c.resources[res.Id()] != res {
+		c.mu.Unlock()
+		return
+	}
+	delete(c.resources, res.Id())
+	c.mu.Unlock()
+	if res.Id() < serverIdStart {
+		// the client may reuse the id now
+		c.display.DeleteId(res.Id())
+	}
+}
+
+// PostError sends a wl_display.error event about res and disconnects
+// the client, as is done for protocol errors.
+func (c *Client) PostError(res Resource, code uint32, msg string) {
+	err := &protocolError{resource: res, code: code, msg: msg}
+	c.display.Error(res, code, msg)
+	c.fail(err)
+}
+
+// Close disconnects the client.
+func (c *Client) Close() {
+	c.fail(ErrClientGone)
+}
+
+// Done returns a channel that is closed when the client disconnects.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the client was disconnected: io.EOF if it
+// hung up, or the protocol error sent to it.  It returns nil while the
+// client is connected.
+func (c *Client) Err() error {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	return c.err
+}
+
+func (c *Client) fail(err error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+	c.conn.Close()
+}
+
+func (c *Client) serve() {
+	defer c.srv.removeClient(c)
+	defer c.r.Close()
+	for {
+		msg, err := c.r.ReadMessage()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		if err := c.dispatch(msg); err != nil {
+			if pe, ok := err.(*protocolError); ok {
+				c.PostError(pe.resource, pe.code, pe.msg)
+			} else {
+				c.fail(err)
+			}
+			return
+		}
+	}
+}
+
+// dispatch hands a request to its resource.
+func (c *Client) dispatch(msg *wire.Message) error {
+	res := c.Resource(msg.Id)
+	if res == nil {
+		return &protocolError{
+			resource: c.display,
+			code:     uint32(wl.DisplayErrorInvalidObject),
+			msg:      fmt.Sprintf("invalid object %d", msg.Id),
+		}
+	}
+	iface := res.Interface()
+	if msg.Opcode >= uint32(len(iface.Requests)) {
+		return &protocolError{
+			resource: res,
+			code:     uint32(wl.DisplayErrorInvalidMethod),
+			msg:      fmt.Sprintf("invalid method %d, object %s@%d", msg.Opcode, iface.Name, msg.Id),
+		}
+	}
+	m := &iface.Requests[msg.Opcode]
+
+	req := &Request{
+		Opcode: msg.Opcode,
+		target: res,
+		dec:    wire.Decoder{Data: msg.Data},
+	}
+	for _, arg := range m.Args {
+		if arg.Type == wl.ArgFd {
+			req.fds++
+		}
+	}
+	if d, ok := res.(Dispatcher); ok {
+		d.Dispatch(req)
+	}
+	// close the fds of requests nobody decoded
+	for ; req.fds > 0; req.fds-- {
+		if fd, ok := c.r.TakeFd(); ok {
+			syscall.Close(fd)
+		}
+	}
+	if req.err == nil && req.dec.Err != nil {
+		req.err = &protocolError{
+			resource: res,
+			code:     uint32(wl.DisplayErrorInvalidMethod),
+			msg:      fmt.Sprintf("%s@%d.%s: %s", iface.Name, msg.Id, m.Name, req.dec.Err),
+		}
+	}
+	if req.err != nil {
+		for _, fd := range req.decodedFds {
+			syscall.Close(fd)
+		}
+		return req.err
+	}
+	if m.Destructor {
+		c.Destroy(res)
+	}
+	return nil
+}
+
+// HandleDisplaySync answers wl_display.sync right away, since all
+// earlier requests have been handled.
+func (c *Client) HandleDisplaySync(req DisplaySyncRequest) {
+	req.Callback.Done(c.srv.NextSerial())
+	c.Destroy(req.Callback)
+}
+
+// HandleDisplayGetRegistry announces the globals to a new registry.
+func (c *Client) HandleDisplayGetRegistry(req DisplayGetRegistryRequest) {
+	registry := req.Registry
+	registry.AddBindHandler(c)
+	c.mu.Lock()
+	c.registries = append(c.registries, registry)
+	c.mu.Unlock()
+	for _, g := range c.srv.Globals() {
+		registry.Global(g.Name, g.Interface.Name, g.Version)
+	}
+}
+
+// HandleRegistryBind creates the resource for a global.
+func (c *Client) HandleRegistryBind(req RegistryBindRequest) {
+	g := c.srv.global(req.Name)
+	if g == nil || g.Interface.Name != req.Interface {
+		c.PostError(c.display, uint32(wl.DisplayErrorInvalidObject),
+			fmt.Sprintf("invalid global %s (%d)", req.Interface, req.Name))
+		return
+	}
+	if req.Version == 0 || req.Version > g.Version {
+		c.PostError(c.display, uint32(wl.DisplayErrorInvalidObject),
+			fmt.Sprintf("invalid version for global %s (%d): have %d, wanted %d",
+				req.Interface, req.Name, g.Version, req.Version))
+		return
+	}
+	newResource := constructors[g.Interface.Name]
+	if newResource == nil {
+		c.PostError(c.display, uint32(wl.DisplayErrorImplementation),
+			fmt.Sprintf("no bindings for %s", req.Interface))
+		return
+	}
+	res := newResource()
+	if err := c.registerAt(res, req.Id, req.Version); err != nil {
+		pe := err.(*protocolError)
+		c.PostError(pe.resource, pe.code, pe.msg)
+		return
+	}
+	if g.bind != nil {
+		g.bind(res)
+	}
+}
+
+// announce tells the registries of the client that g has been added
+// or removed.
+func (c *Client) announce(g *Global, added bool) {
+	c.mu.RLock()
+	registries := append([]*Registry(nil), c.registries...)
+	c.mu.RUnlock()
+	for _, registry := range registries {
+		if added {
+			registry.Global(g.Name, g.Interface.Name, g.Version)
+		} else {
+			registry.GlobalRemove(g.Name)
+		}
+	}
+}
+
+// protocolError is a fatal error caused by the client.
+type protocolError struct {
+	resource Resource
+	code     uint32
+	msg      string
+}
+
+func (e *protocolError) Error() string {
+	return fmt.Sprintf("%s@%d: error %d: %s", e.resource.Interface().Name, e.resource.Id(), e.code, e.msg)
+}
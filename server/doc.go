@@ -0,0 +1,8 @@
+// Package server implements the server side of the wayland protocol:
+// a Server accepts the connections of clients, and the requests of
+// each Client are dispatched to the handlers of its resources.  The
+// resources of the core protocol in protocol.go are generated from
+// protocol/wayland.xml by cmd/wl-scanner.
+package server
+
+//go:generate go run ../cmd/wl-scanner -pkg server -server -source ../protocol/wayland.xml -output protocol.go
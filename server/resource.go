@@ -0,0 +1,217 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+)
+
+// A Resource is the server side of a protocol object of a client.
+type Resource interface {
+	Client() *Client
+	Id() uint32
+	Version() uint32
+	Interface() *wl.Interface
+	base() *BaseResource
+}
+
+type Dispatcher interface {
+	Dispatch(*Request)
+}
+
+// BaseResource is embedded by all resource types.
+type BaseResource struct {
+	id      uint32
+	version uint32
+	client  *Client
+}
+
+func (r *BaseResource) Id() uint32 {
+	return r.id
+}
+
+// Version returns the version of the interface the client uses for
+// the resource.
+func (r *BaseResource) Version() uint32 {
+	return r.version
+}
+
+func (r *BaseResource) Client() *Client {
+	return r.client
+}
+
+func (r *BaseResource) base() *BaseResource {
+	return r
+}
+
+// constructors maps interface names to functions returning a new
+// resource of the interface, for the resources created by
+// wl_registry.bind.
+var constructors = make(map[string]func() Resource)
+
+// RegisterInterface makes the resource type returned by newResource
+// known for the interface, so that clients can bind globals of it.
+// The generated bindings register their types.
+func RegisterInterface(iface *wl.Interface, newResource func() Resource) {
+	constructors[iface.Name] = newResource
+}
+
+// A Request is a message from a client, decoded by the Dispatch method
+// of its resource.
+type Request struct {
+	Opcode uint32
+	target Resource
+	dec    wire.Decoder
+	// fds is the number of fd arguments not decoded yet, and
+	// decodedFds those decoded, which are closed if the request fails
+	fds        int
+	decodedFds []int
+	err        error
+}
+
+func (req *Request) fail(err error) {
+	if req.err == nil {
+		req.err = err
+	}
+}
+
+// Err returns the first error decoding the request, if any.  The
+// handlers do not see a request that failed, its file descriptors are
+// closed, and the client is disconnected with the error once it has
+// been dispatched.
+func (req *Request) Err() error {
+	if req.err != nil {
+		return req.err
+	}
+	return req.dec.Err
+}
+
+func (req *Request) Uint32() uint32 {
+	return req.dec.Uint32()
+}
+
+func (req *Request) Int32() int32 {
+	return req.dec.Int32()
+}
+
+func (req *Request) Fixed() wl.Fixed {
+	return req.dec.Fixed()
+}
+
+func (req *Request) String() string {
+	return req.dec.String()
+}
+
+// Array returns the bytes of an array argument, which wl.Uint32s
+// decodes if it holds uint32 values.
+func (req *Request) Array() []byte {
+	return req.dec.Array()
+}
+
+// FD returns the next file descriptor argument, which then belongs to
+// the caller.
+func (req *Request) FD() uintptr {
+	c := req.target.Client()
+	fd, ok := -1, false
+	if req.fds > 0 {
+		req.fds--
+		fd, ok = c.r.TakeFd()
+	}
+	if !ok {
+		req.fail(fmt.Errorf("%s@%d: missing file descriptor", req.target.Interface().Name, req.target.Id()))
+		return ^uintptr(0)
+	}
+	req.decodedFds = append(req.decodedFds, fd)
+	return uintptr(fd)
+}
+
+// Object returns the resource of an object argument of the interface
+// named iface, or of any interface if iface is empty, and nil for a
+// null argument.  The request fails with an invalid_object error for
+// an unknown object, an object of another interface, or a null
+// argument unless allowNull is set.
+func (req *Request) Object(iface string, allowNull bool) Resource {
+	c := req.target.Client()
+	id := req.Uint32()
+	var msg string
+	r := c.Resource(id)
+	switch {
+	case id == 0 && allowNull:
+		return nil
+	case id == 0:
+		msg = "null object for a non-nullable argument"
+	case r == nil:
+		msg = fmt.Sprintf("invalid object %d", id)
+	case iface != "" && r.Interface().Name != iface:
+		msg = fmt.Sprintf("object %d is a %s, not a %s", id, r.Interface().Name, iface)
+	default:
+		return r
+	}
+	req.fail(&protocolError{
+		resource: c.display,
+		code:     uint32(wl.DisplayErrorInvalidObject),
+		msg:      msg,
+	})
+	return nil
+}
+
+// NewId registers res under the id of a new_id argument, with the
+// version of the resource the request was sent to.
+func (req *Request) NewId(res Resource) Resource {
+	c := req.target.Client()
+	id := req.Uint32()
+	if err := c.registerAt(res, id, req.target.Version()); err != nil {
+		req.fail(err)
+	}
+	return res
+}
+
+// SendEvent sends an event of res to its client.  Events are written
+// right away.
+func (c *Client) SendEvent(res Resource, opcode uint32, args ...interface{}) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	var e wire.Encoder
+	for _, arg := range args {
+		switch t := arg.(type) {
+		case Resource:
+			if v := reflect.ValueOf(t); v.Kind() == reflect.Ptr && v.IsNil() {
+				e.PutUint32(0)
+				break
+			}
+			if b := t.base(); b.version == 0 {
+				// a new object created by the server for this
+				// event takes the version of res
+				b.version = res.Version()
+			}
+			e.PutUint32(t.Id())
+		case nil:
+			e.PutUint32(0)
+		case uint32:
+			e.PutUint32(t)
+		case int32:
+			e.PutInt32(t)
+		case wl.Fixed:
+			e.PutFixed(t)
+		case string:
+			e.PutString(t)
+		case []byte:
+			e.PutArray(t)
+		case uintptr:
+			e.PutFd(int(t))
+		default:
+			panic("Invalid Wayland event parameter type.")
+		}
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if err := e.WriteMessage(c.conn, res.Id(), opcode); err != nil {
+		c.fail(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dkolbly/wl"
+)
+
+// A BindFunc is called when a client binds a global, with the new
+// resource already registered.  It typically adds the handlers of the
+// resource and sends its initial events.
+type BindFunc func(res Resource)
+
+// A Global is an object advertised to clients through wl_registry.
+type Global struct {
+	Name      uint32
+	Interface *wl.Interface
+	Version   uint32
+	bind      BindFunc
+}
+
+// Server is the server side of the wayland protocol.  It accepts
+// clients on its sockets and advertises its globals to them.
+type Server struct {
+	mu        sync.Mutex
+	listeners []*net.UnixListener
+	clients   []*Client
+	globals   []*Global
+	lastName  uint32
+	serial    uint32
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+func NewServer() *Server {
+	return new(Server)
+}
+
+// AddSocket listens for clients on a socket.  A relative name is
+// resolved against XDG_RUNTIME_DIR, like WAYLAND_DISPLAY by clients; an
+// empty name picks the first free one of wayland-0 to wayland-32.  It
+// returns the name the socket was created under.
+func (s *Server) AddSocket(name string) (string, error) {
+	if name == "" {
+		for i := 0; i <= 32; i++ {
+			name = fmt.Sprintf("wayland-%d", i)
+			if _, err := s.AddSocket(name); err == nil {
+				return name, nil
+			}
+		}
+		return "", errors.New("no free wayland display name")
+	}
+
+	path := name
+	if !filepath.IsAbs(path) {
+		dir := os.Getenv("XDG_RUNTIME_DIR")
+		if dir == "" {
+			return "", errors.New("XDG_RUNTIME_DIR not set in the environment")
+		}
+		path = filepath.Join(dir, name)
+	}
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		ln.Close()
+		return "", errors.New("server closed")
+	}
+	s.listeners = append(s.listeners, ln)
+	s.wg.Add(1)
+	go s.accept(ln)
+	return name, nil
+}
+
+func (s *Server) accept(ln *net.UnixListener) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.AcceptUnix()
+		if err != nil {
+			return
+		}
+		s.AddClient(conn)
+	}
+}
+
+// AddClient serves a client connected through conn, e.g. one end of a
+// socketpair handed to a child process.
+func (s *Server) AddClient(conn *net.UnixConn) *Client {
+	c := newClient(s, conn)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		c.Close()
+		return c
+	}
+	s.clients = append(s.clients, c)
+	s.wg.Add(1)
+	go c.serve()
+	return c
+}
+
+func (s *Server) removeClient(c *Client) {
+	defer s.wg.Done()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, other := range s.clients {
+		if other == c {
+			s.clients = append(s.clients[:i], s.clients[i+1:]...)
+			break
+		}
+	}
+}
+
+// Clients returns the clients currently connected.
+func (s *Server) Clients() []*Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Client(nil), s.clients...)
+}
+
+// AddGlobal advertises an interface to clients at the given version,
+// and calls bind for each resource clients bind to it.
+func (s *Server) AddGlobal(iface *wl.Interface, version uint32, bind BindFunc) *Global {
+	s.mu.Lock()
+	s.lastName++
+	g := &Global{
+		Name:      s.lastName,
+		Interface: iface,
+		Version:   version,
+		bind:      bind,
+	}
+	s.globals = append(s.globals, g)
+	clients := append([]*Client(nil), s.clients...)
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		c.announce(g, true)
+	}
+	return g
+}
+
+// RemoveGlobal withdraws a global.  Resources already bound to it stay
+// alive.
+func (s *Server) RemoveGlobal(g *Global) {
+	s.mu.Lock()
+	for i, other := range s.globals {
+		if other == g {
+			s.globals = append(s.globals[:i], s.globals[i+1:]...)
+			break
+		}
+	}
+	clients := append([]*Client(nil), s.clients...)
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		c.announce(g, false)
+	}
+}
+
+// Globals returns the globals currently advertised.
+func (s *Server) Globals() []*Global {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Global(nil), s.globals...)
+}
+
+func (s *Server) global(name uint32) *Global {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, g := range s.globals {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// NextSerial returns a new serial for an event.
+func (s *Server) NextSerial() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serial++
+	return s.serial
+}
+
+// Close stops listening, disconnects all clients and waits for their
+// goroutines to finish.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	listeners := s.listeners
+	clients := append([]*Client(nil), s.clients...)
+	s.listeners = nil
+	s.mu.Unlock()
+
+	for _, ln := range listeners {
+		ln.Close()
+	}
+	for _, c := range clients {
+		c.Close()
+	}
+	s.wg.Wait()
+	return nil
+}
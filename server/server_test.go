@@ -0,0 +1,228 @@
+package server_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/server"
+	sxdg "github.com/dkolbly/wl/server/xdg"
+	"github.com/dkolbly/wl/xdg"
+)
+
+func listen(t *testing.T) *server.Server {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	s := server.NewServer()
+	name, err := s.AddSocket("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WAYLAND_DISPLAY", name)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func connect(t *testing.T) (*wl.Display, *wl.Registry, globalNames) {
+	display, err := wl.Connect("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { display.Context().Close() })
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	globals := make(globalNames)
+	registry.AddGlobalHandler(globals)
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return display, registry, globals
+}
+
+type globalNames map[string]uint32
+
+func (g globalNames) HandleRegistryGlobal(ev wl.RegistryGlobalEvent) {
+	g[ev.Interface] = ev.Name
+}
+
+// compositor records the surfaces of a client and their commits.
+type compositor struct {
+	mu       sync.Mutex
+	surfaces []*server.Surface
+	commits  int
+}
+
+func (c *compositor) HandleCompositorCreateSurface(req server.CompositorCreateSurfaceRequest) {
+	c.mu.Lock()
+	c.surfaces = append(c.surfaces, req.Id)
+	c.mu.Unlock()
+	req.Id.AddCommitHandler(c)
+}
+
+func (c *compositor) HandleSurfaceCommit(server.SurfaceCommitRequest) {
+	c.mu.Lock()
+	c.commits++
+	c.mu.Unlock()
+}
+
+type capsRecorder struct {
+	caps wl.SeatCapability
+}
+
+func (r *capsRecorder) HandleSeatCapabilities(ev wl.SeatCapabilitiesEvent) {
+	r.caps = ev.Capabilities
+}
+
+func TestBindAndDispatch(t *testing.T) {
+	s := listen(t)
+	comp := new(compositor)
+	s.AddGlobal(wl.CompositorInterface, 4, func(res server.Resource) {
+		res.(*server.Compositor).AddCreateSurfaceHandler(comp)
+	})
+	s.AddGlobal(wl.SeatInterface, 5, func(res server.Resource) {
+		res.(*server.Seat).Capabilities(wl.SeatCapabilityKeyboard)
+	})
+
+	display, registry, globals := connect(t)
+	c := display.Context()
+	wlcomp := wl.NewCompositor(c)
+	registry.Bind(globals["wl_compositor"], "wl_compositor", 4, wlcomp)
+	seat := wl.NewSeat(c)
+	caps := new(capsRecorder)
+	seat.AddCapabilitiesHandler(caps)
+	registry.Bind(globals["wl_seat"], "wl_seat", 5, seat)
+	surface, _ := wlcomp.CreateSurface()
+	surface.Commit()
+	surface.Commit()
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if caps.caps != wl.SeatCapabilityKeyboard {
+		t.Errorf("unexpected capabilities %d", caps.caps)
+	}
+	comp.mu.Lock()
+	defer comp.mu.Unlock()
+	if len(comp.surfaces) != 1 || comp.commits != 2 {
+		t.Fatalf("got %d surfaces, %d commits", len(comp.surfaces), comp.commits)
+	}
+	if sf := comp.surfaces[0]; sf.Id() != uint32(surface.Id()) || sf.Version() != 4 {
+		t.Errorf("surface %d has version %d", sf.Id(), sf.Version())
+	}
+}
+
+type errorRecorder struct {
+	err *wl.DisplayErrorEvent
+}
+
+func (r *errorRecorder) HandleDisplayError(ev wl.DisplayErrorEvent) {
+	r.err = &ev
+}
+
+func TestBindVersionError(t *testing.T) {
+	s := listen(t)
+	s.AddGlobal(wl.CompositorInterface, 3, nil)
+
+	display, registry, globals := connect(t)
+	c := display.Context()
+	rec := new(errorRecorder)
+	display.AddErrorHandler(rec)
+	registry.Bind(globals["wl_compositor"], "wl_compositor", 4, wl.NewCompositor(c))
+	c.Flush()
+	<-c.Done()
+
+	if rec.err == nil || rec.err.Code != uint32(wl.DisplayErrorInvalidObject) {
+		t.Errorf("unexpected error %+v", rec.err)
+	}
+}
+
+type wmBase struct {
+	surfaces chan *sxdg.Surface
+}
+
+func (wm *wmBase) HandleWmBaseGetXdgSurface(req sxdg.WmBaseGetXdgSurfaceRequest) {
+	wm.surfaces <- req.Id
+}
+
+type configureRecorder struct {
+	serials chan uint32
+}
+
+func (r *configureRecorder) HandleSurfaceConfigure(ev xdg.SurfaceConfigureEvent) {
+	r.serials <- ev.Serial
+}
+
+func TestXdgShell(t *testing.T) {
+	s := listen(t)
+	wm := &wmBase{surfaces: make(chan *sxdg.Surface, 1)}
+	s.AddGlobal(wl.CompositorInterface, 4, nil)
+	s.AddGlobal(xdg.WmBaseInterface, 2, func(res server.Resource) {
+		res.(*sxdg.WmBase).AddGetXdgSurfaceHandler(wm)
+	})
+
+	display, registry, globals := connect(t)
+	c := display.Context()
+	wlcomp := wl.NewCompositor(c)
+	registry.Bind(globals["wl_compositor"], "wl_compositor", 4, wlcomp)
+	base := xdg.NewWmBase(c)
+	registry.Bind(globals["xdg_wm_base"], "xdg_wm_base", 2, base)
+	surface, _ := wlcomp.CreateSurface()
+	xs, _ := base.GetXdgSurface(surface)
+	rec := &configureRecorder{serials: make(chan uint32, 1)}
+	xs.AddConfigureHandler(rec)
+	c.Flush()
+
+	sxs := <-wm.surfaces
+	serial := s.NextSerial()
+	if err := sxs.Configure(serial); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-rec.serials; got != serial {
+		t.Errorf("configured with serial %d, expected %d", got, serial)
+	}
+}
+
+func TestInvalidObjectArgument(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		surface func(*wl.Compositor) interface{}
+	}{
+		{"wrong interface", func(comp *wl.Compositor) interface{} { return comp }},
+		{"null", func(*wl.Compositor) interface{} { return uint32(0) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := listen(t)
+			wm := &wmBase{surfaces: make(chan *sxdg.Surface, 1)}
+			s.AddGlobal(wl.CompositorInterface, 4, nil)
+			s.AddGlobal(xdg.WmBaseInterface, 2, func(res server.Resource) {
+				res.(*sxdg.WmBase).AddGetXdgSurfaceHandler(wm)
+			})
+
+			display, registry, globals := connect(t)
+			c := display.Context()
+			rec := new(errorRecorder)
+			display.AddErrorHandler(rec)
+			wlcomp := wl.NewCompositor(c)
+			registry.Bind(globals["wl_compositor"], "wl_compositor", 4, wlcomp)
+			base := xdg.NewWmBase(c)
+			registry.Bind(globals["xdg_wm_base"], "xdg_wm_base", 2, base)
+			// xdg_wm_base.get_xdg_surface takes a non-nullable wl_surface
+			if err := c.SendRequest(base, 2, wl.Proxy(xdg.NewSurface(c)), tc.surface(wlcomp)); err != nil {
+				t.Fatal(err)
+			}
+			c.Flush()
+			<-c.Done()
+
+			if rec.err == nil || rec.err.Code != uint32(wl.DisplayErrorInvalidObject) {
+				t.Errorf("unexpected error %+v", rec.err)
+			}
+			select {
+			case <-wm.surfaces:
+				t.Error("handler ran with an invalid surface")
+			default:
+			}
+		})
+	}
+}
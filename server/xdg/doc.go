@@ -0,0 +1,6 @@
+// Package xdg implements the server side of the xdg_shell protocol,
+// generated from protocol/xdg-shell.xml.  The protocol metadata is
+// shared with the client package github.com/dkolbly/wl/xdg.
+package xdg
+
+//go:generate go run ../../cmd/wl-scanner -pkg xdg -server -client github.com/dkolbly/wl/xdg -source ../../protocol/xdg-shell.xml -output protocol.go
@@ -0,0 +1,33 @@
+package wl
+
+import "sync"
+
+// EventChan delivers the events of a subscription on a channel instead
+// of a callback.  subscribe is one of the generated On methods, such as
+// seat.OnCapabilities:
+//
+//	caps, cancel := wl.EventChan(seat.OnCapabilities, 1)
+//	defer cancel()
+//	ev := <-caps
+//
+// Dispatch waits for the channel to have room, so it should be read
+// promptly, or be buffered for the events expected while it is not.
+// cancel ends the subscription and unblocks a dispatch waiting on the
+// channel, dropping that event.  The channel is never closed.
+func EventChan[E any](subscribe func(func(E)) func(), buffer int) (<-chan E, func()) {
+	ch := make(chan E, buffer)
+	done := make(chan struct{})
+	unsubscribe := subscribe(func(ev E) {
+		select {
+		case ch <- ev:
+		case <-done:
+		}
+	})
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			close(done)
+			unsubscribe()
+		})
+	}
+}
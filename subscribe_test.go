@@ -0,0 +1,53 @@
+package wl
+
+import (
+	"testing"
+	"time"
+)
+
+func doneEvent(cb *Callback, data uint32) *Event {
+	return &Event{pid: cb.Id(), Opcode: 0, data: uint32Data(data)}
+}
+
+func TestOnEvent(t *testing.T) {
+	cb := NewCallback(newTestContext(t))
+	var got []uint32
+	record := func(ev CallbackDoneEvent) {
+		got = append(got, ev.CallbackData)
+	}
+	cancel1 := cb.OnDone(record)
+	cancel2 := cb.OnDone(record)
+	cb.Dispatch(doneEvent(cb, 1))
+	cancel1()
+	cb.Dispatch(doneEvent(cb, 2))
+	cancel2()
+	cancel2()
+	cb.Dispatch(doneEvent(cb, 3))
+	if len(got) != 3 || got[0] != 1 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestEventChan(t *testing.T) {
+	cb := NewCallback(newTestContext(t))
+	ch, cancel := EventChan(cb.OnDone, 1)
+	cb.Dispatch(doneEvent(cb, 7))
+	if ev := <-ch; ev.CallbackData != 7 {
+		t.Errorf("got %d", ev.CallbackData)
+	}
+
+	// a dispatch blocked on the full channel is released by cancel
+	cb.Dispatch(doneEvent(cb, 8))
+	blocked := make(chan struct{})
+	go func() {
+		cb.Dispatch(doneEvent(cb, 9))
+		close(blocked)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-blocked
+	cb.Dispatch(doneEvent(cb, 10))
+	if ev := <-ch; ev.CallbackData != 8 || len(ch) != 0 {
+		t.Errorf("got %d, then %d more", ev.CallbackData, len(ch))
+	}
+}
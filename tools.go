@@ -1,7 +0,0 @@
-// +build tools
-
-package tools
-
-import (
-	_ "github.com/dkolbly/wl-scanner"
-)
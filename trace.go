@@ -0,0 +1,183 @@
+package wl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Trace makes the Context log every request it sends and every event
+// it dispatches to w, in the format of libwayland's WAYLAND_DEBUG
+// output.  Connect enables tracing to standard error when WAYLAND_DEBUG
+// is set to 1 or client.  Requests may be sent from any goroutine, so
+// the writes to w are serialized.
+func Trace(w io.Writer) Option {
+	return func(c *Context) {
+		var mu sync.Mutex
+		c.trace = func(line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			io.WriteString(w, line+"\n")
+		}
+	}
+}
+
+// TraceLogger is like Trace, but prints each line with l.
+func TraceLogger(l *log.Logger) Option {
+	return func(c *Context) {
+		c.trace = func(line string) {
+			l.Print(line)
+		}
+	}
+}
+
+// traceRequest logs a request about to be queued.
+func (c *Context) traceRequest(proxy Proxy, r *Request) {
+	fds := make([]int, len(r.fds))
+	for i, fd := range r.fds {
+		fds[i] = int(fd)
+	}
+	c.traceMessage(" -> ", proxy.Interface(), r.pid, r.opcode, proxy.Interface().request(r.opcode), r.data, fds)
+}
+
+// traceEvent logs an event about to be dispatched to proxy, which is
+// nil if the target is unknown, or one that is discarded because its
+// target has been destroyed.
+func (c *Context) traceEvent(proxy Proxy, ev *Event, discarded bool) {
+	prefix := ""
+	if discarded || proxy == nil {
+		prefix = "discarded "
+	}
+	var iface *Interface
+	if proxy != nil {
+		iface = proxy.Interface()
+	}
+	c.traceMessage(prefix, iface, ev.pid, ev.Opcode, iface.event(ev.Opcode), ev.data, ev.fds)
+}
+
+func (c *Context) traceMessage(prefix string, iface *Interface, id ProxyId, opcode uint32, msg *Message, data []byte, fds []int) {
+	var b strings.Builder
+	b.WriteString(prefix)
+	name := "[unknown]"
+	if iface != nil {
+		name = iface.Name
+	}
+	fmt.Fprintf(&b, "%s@%d.", name, id)
+	if msg == nil {
+		fmt.Fprintf(&b, "[opcode %d]()", opcode)
+		c.traceLine(b.String())
+		return
+	}
+	b.WriteString(msg.Name)
+	b.WriteByte('(')
+	r := traceReader{data: data}
+	for i, arg := range msg.Args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		switch arg.Type {
+		case ArgInt:
+			fmt.Fprintf(&b, "%d", int32(r.uint32()))
+		case ArgUint:
+			fmt.Fprintf(&b, "%d", r.uint32())
+		case ArgFixed:
+			fmt.Fprintf(&b, "%f", Fixed(r.uint32()).Float64())
+		case ArgString:
+			if s, ok := r.string(); ok {
+				fmt.Fprintf(&b, "%q", s)
+			} else {
+				b.WriteString("nil")
+			}
+		case ArgObject:
+			id := ProxyId(r.uint32())
+			if id == 0 {
+				b.WriteString("nil")
+			} else {
+				fmt.Fprintf(&b, "%s@%d", c.traceInterfaceName(arg.Interface, id), id)
+			}
+		case ArgNewId:
+			iface := arg.Interface
+			if iface == "" {
+				// wl_registry.bind spells out the interface
+				iface, _ = r.string()
+				r.uint32()
+			}
+			id := r.uint32()
+			if id == 0 {
+				b.WriteString("nil")
+			} else {
+				fmt.Fprintf(&b, "new id %s@%d", iface, id)
+			}
+		case ArgArray:
+			fmt.Fprintf(&b, "array[%d]", len(r.array()))
+		case ArgFd:
+			if len(fds) > 0 {
+				fmt.Fprintf(&b, "fd %d", fds[0])
+				fds = fds[1:]
+			} else {
+				b.WriteString("fd ?")
+			}
+		}
+	}
+	b.WriteByte(')')
+	c.traceLine(b.String())
+}
+
+// traceInterfaceName returns the interface of the object argument id,
+// which is iface unless the protocol allows any interface.
+func (c *Context) traceInterfaceName(iface string, id ProxyId) string {
+	if iface != "" {
+		return iface
+	}
+	if p, _ := c.lookupObject(id); p != nil && p.Interface() != nil {
+		return p.Interface().Name
+	}
+	return "[unknown]"
+}
+
+func (c *Context) traceLine(line string) {
+	us := time.Now().UnixNano() / 1000
+	c.trace(fmt.Sprintf("[%7d.%03d] %s", us/1000%10000000, us%1000, line))
+}
+
+// traceReader decodes message arguments for tracing, yielding zero
+// values instead of failing on truncated messages.
+type traceReader struct {
+	data []byte
+}
+
+func (r *traceReader) next(n int) []byte {
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	buf := r.data[:n]
+	r.data = r.data[n:]
+	return buf
+}
+
+func (r *traceReader) uint32() uint32 {
+	buf := r.next(4)
+	if len(buf) < 4 {
+		return 0
+	}
+	return Order.Uint32(buf)
+}
+
+func (r *traceReader) array() []byte {
+	n := int(r.uint32())
+	buf := r.next(n)
+	r.next((4 - n&3) & 3)
+	return buf
+}
+
+func (r *traceReader) string() (string, bool) {
+	buf := r.array()
+	if len(buf) == 0 {
+		return "", false
+	}
+	return string(bytes.TrimRight(buf, "\x00")), true
+}
@@ -0,0 +1,53 @@
+package wl
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	c := newTestContext(t)
+	var buf bytes.Buffer
+	Trace(&buf)(c)
+	display := NewDisplay(c)
+
+	if _, err := display.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Bind(1, "wl_seat", 5, NewSeat(c)); err != nil {
+		t.Fatal(err)
+	}
+	global := uint32Data(uint32(registry.Id()), 0, 1, 14)
+	global = append(global, "wl_compositor\x00\x00\x00"...)
+	global = append(global, uint32Data(4)...)
+	Order.PutUint32(global[4:], uint32(len(global))<<16)
+	c.dispatch(&Event{pid: registry.Id(), Opcode: 0, data: global[8:], proxy: registry})
+	c.dispatch(&Event{pid: 99, Opcode: 2})
+
+	want := []string{
+		` -> wl_display@1.sync(new id wl_callback@2)`,
+		` -> wl_display@1.get_registry(new id wl_registry@3)`,
+		` -> wl_registry@3.bind(1, new id wl_seat@4)`,
+		`wl_registry@3.global(1, "wl_compositor", 4)`,
+		`discarded [unknown]@99.[opcode 2]()`,
+	}
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got:\n%s", len(want), buf.Bytes())
+	}
+	stamp := regexp.MustCompile(`^\[ *[0-9]+\.[0-9]{3}\] `)
+	for i, line := range lines {
+		if !stamp.Match(line) {
+			t.Errorf("line %d has no timestamp: %s", i, line)
+			continue
+		}
+		if got := string(stamp.ReplaceAll(line, nil)); got != want[i] {
+			t.Errorf("line %d: got %s, want %s", i, got, want[i])
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
 	"syscall"
 )
@@ -16,6 +16,7 @@ type Display struct {
 	mu                sync.RWMutex
 	display           *wl.Display
 	registry          *wl.Registry
+	globals           *wl.Globals
 	compositor        *wl.Compositor
 	subCompositor     *wl.Subcompositor
 	shell             *wl.Shell
@@ -37,7 +38,6 @@ func Connect(addr string) (*Display, error) {
 	}
 
 	d.display = display
-	display.AddErrorHandler(d)
 
 	err = d.registerGlobals()
 	if err != nil {
@@ -76,176 +76,88 @@ func (d *Display) Context() *wl.Context {
 	return d.display.Context()
 }
 
-type registrar struct {
-	ch chan wl.RegistryGlobalEvent
-}
-
-func (r registrar) HandleRegistryGlobal(ev wl.RegistryGlobalEvent) {
-	r.ch <- ev
-}
-
-type doner struct {
-	ch chan wl.CallbackDoneEvent
-}
-
-func (d doner) HandleCallbackDone(ev wl.CallbackDoneEvent) {
-	d.ch <- ev
-}
-
 func (d *Display) registerGlobals() error {
 	registry, err := d.display.GetRegistry()
 	if err != nil {
 		return fmt.Errorf("Display.GetRegistry failed : %s", err)
 	}
 	d.registry = registry
+	d.globals = wl.NewGlobals(registry)
 
-	callback, err := d.display.Sync()
-	if err != nil {
-		return fmt.Errorf("Display.Sync failed %s", err)
+	if err := d.display.Roundtrip(context.Background()); err != nil {
+		return fmt.Errorf("registering globals: %s", err)
 	}
 
-	rgeChan := make(chan wl.RegistryGlobalEvent)
-	rgeHandler := registrar{rgeChan}
-	registry.AddGlobalHandler(rgeHandler)
-
-	cdeChan := make(chan wl.CallbackDoneEvent)
-	cdeHandler := doner{cdeChan}
-
-	callback.AddDoneHandler(cdeHandler)
-loop:
-	for {
-		select {
-		case ev := <-rgeChan:
-			if err := d.registerInterface(registry, ev); err != nil {
-				return err
-			}
-		case <-cdeChan:
-			break loop
-		}
+	// the globals that are missing stay nil, and those that are required
+	// are caught by checkGlobalsRegistered
+	d.shm, _ = wl.BindGlobal(d.globals, wl.NewShm, wl.ShmInterface.Version)
+	d.compositor, _ = wl.BindGlobal(d.globals, wl.NewCompositor, wl.CompositorInterface.Version)
+	d.shell, _ = wl.BindGlobal(d.globals, wl.NewShell, wl.ShellInterface.Version)
+	d.seat, _ = wl.BindGlobal(d.globals, wl.NewSeat, wl.SeatInterface.Version)
+	d.dataDeviceManager, _ = wl.BindGlobal(d.globals, wl.NewDataDeviceManager, wl.DataDeviceManagerInterface.Version)
+	d.subCompositor, _ = wl.BindGlobal(d.globals, wl.NewSubcompositor, wl.SubcompositorInterface.Version)
+	d.wmBase, _ = wl.BindGlobal(d.globals, xdg.NewWmBase, xdg.WmBaseInterface.Version)
+	if d.wmBase != nil {
+		d.wmBase.AddPingHandler(d)
+	}
+	// versions are clamped, so binding only fails with the connection
+	if err := d.Context().Err(); err != nil {
+		return fmt.Errorf("registering globals: %s", err)
 	}
-
-	registry.RemoveGlobalHandler(rgeHandler)
-	callback.RemoveDoneHandler(cdeHandler)
 	return nil
 }
 
-type seatcap struct {
-	ch chan wl.SeatCapabilitiesEvent
-}
-
-func (sce seatcap) HandleSeatCapabilities(ev wl.SeatCapabilitiesEvent) {
-	sce.ch <- ev
+// Globals returns the globals of the compositor, to bind those the
+// Display does not, or to follow outputs and seats being plugged in.
+func (d *Display) Globals() *wl.Globals {
+	return d.globals
 }
 
 func (d *Display) registerInputs() error {
-	callback, err := d.display.Sync()
-	if err != nil {
-		return fmt.Errorf("Display.Sync failed %s", err)
+	var mu sync.Mutex
+	var caps wl.SeatCapability
+	cancel := d.seat.OnCapabilities(func(ev wl.SeatCapabilitiesEvent) {
+		mu.Lock()
+		caps = ev.Capabilities
+		mu.Unlock()
+	})
+	defer cancel()
+
+	if err := d.display.Roundtrip(context.Background()); err != nil {
+		return fmt.Errorf("registering inputs: %s", err)
 	}
 
-	cdeChan := make(chan wl.CallbackDoneEvent)
-	cdeHandler := doner{cdeChan}
-	callback.AddDoneHandler(cdeHandler)
-
-	sceChan := make(chan wl.SeatCapabilitiesEvent)
-	sceHandler := seatcap{sceChan}
-	d.seat.AddCapabilitiesHandler(sceHandler)
-
-loop:
-	for {
-		select {
-		case ev := <-sceChan:
-			if (ev.Capabilities & wl.SeatCapabilityPointer) != 0 {
-				pointer, err := d.seat.GetPointer()
-				if err != nil {
-					return fmt.Errorf("unable to get Pointer object: %s", err)
-				}
-				d.pointer = pointer
-			}
-			if (ev.Capabilities & wl.SeatCapabilityKeyboard) != 0 {
-				keyboard, err := d.seat.GetKeyboard()
-				if err != nil {
-					return fmt.Errorf("unable to get Keyboard object: %s", err)
-				}
-				d.keyboard = keyboard
-			}
-			if (ev.Capabilities & wl.SeatCapabilityTouch) != 0 {
-				touch, err := d.seat.GetTouch()
-				if err != nil {
-					return fmt.Errorf("unable to get Touch object: %s", err)
-				}
-				d.touch = touch
-			}
-		case <-cdeChan:
-			break loop
-		}
-	}
-
-	d.seat.RemoveCapabilitiesHandler(sceHandler)
-	callback.RemoveDoneHandler(cdeHandler)
-
-	return nil
-}
+	mu.Lock()
+	defer mu.Unlock()
 
-func (d *Display) registerInterface(registry *wl.Registry, ev wl.RegistryGlobalEvent) error {
-	fmt.Printf("we discovered an interface: %q\n", ev.Interface)
-	switch ev.Interface {
-	case "wl_shm":
-		ret := wl.NewShm(d.Context())
-		err := registry.Bind(ev.Name, ev.Interface, ev.Version, ret)
-		if err != nil {
-			return fmt.Errorf("unable to bind Shm interface: %s", err)
-		}
-		d.shm = ret
-	case "wl_compositor":
-		ret := wl.NewCompositor(d.Context())
-		err := registry.Bind(ev.Name, ev.Interface, ev.Version, ret)
-		if err != nil {
-			return fmt.Errorf("unable to bind Compositor interface: %s", err)
-		}
-		d.compositor = ret
-	case "wl_shell":
-		ret := wl.NewShell(d.Context())
-		err := registry.Bind(ev.Name, ev.Interface, ev.Version, ret)
-		if err != nil {
-			return fmt.Errorf("unable to bind Shell interface: %s", err)
-		}
-		d.shell = ret
-	case "wl_seat":
-		ret := wl.NewSeat(d.Context())
-		err := registry.Bind(ev.Name, ev.Interface, ev.Version, ret)
-		if err != nil {
-			return fmt.Errorf("unable to bind Seat interface: %s", err)
-		}
-		d.seat = ret
-	case "wl_data_device_manager":
-		ret := wl.NewDataDeviceManager(d.Context())
-		err := registry.Bind(ev.Name, ev.Interface, ev.Version, ret)
+	if caps.Has(wl.SeatCapabilityPointer) {
+		pointer, err := d.seat.GetPointer()
 		if err != nil {
-			return fmt.Errorf("unable to bind DataDeviceManager interface: %s", err)
+			return fmt.Errorf("unable to get Pointer object: %s", err)
 		}
-		d.dataDeviceManager = ret
-	case "wl_subcompositor":
-		ret := wl.NewSubcompositor(d.Context())
-		err := registry.Bind(ev.Name, ev.Interface, ev.Version, ret)
+		d.pointer = pointer
+	}
+	if caps.Has(wl.SeatCapabilityKeyboard) {
+		keyboard, err := d.seat.GetKeyboard()
 		if err != nil {
-			return fmt.Errorf("unable to bind Subcompositor interface: %s", err)
+			return fmt.Errorf("unable to get Keyboard object: %s", err)
 		}
-		d.subCompositor = ret
-	case "zxdg_shell_v6":
-		ret := xdg.NewWmBase(d.Context())
-		err := registry.Bind(ev.Name, ev.Interface, ev.Version, ret)
+		d.keyboard = keyboard
+	}
+	if caps.Has(wl.SeatCapabilityTouch) {
+		touch, err := d.seat.GetTouch()
 		if err != nil {
-			return fmt.Errorf("unable to bind Subcompositor interface: %s", err)
+			return fmt.Errorf("unable to get Touch object: %s", err)
 		}
-		d.wmBase = ret
-		d.wmBase.AddPingHandler(d)
+		d.touch = touch
 	}
 	return nil
 }
 
-func (d *Display) HandleDisplayError(ev wl.DisplayErrorEvent) {
-	log.Fatalf("Display Error Event: %d - %s - %d", ev.ObjectId.Id(), ev.Message, ev.Code)
+// Err returns the error that ended the connection, such as a
+// *wl.ProtocolError sent by the compositor, or nil while it is up.
+func (d *Display) Err() error {
+	return d.display.Context().Err()
 }
 
 func (d *Display) newBuffer(width, height, stride int32) (*wl.Buffer, []byte, error) {
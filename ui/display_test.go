@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/dkolbly/wl/wltest"
+	"github.com/dkolbly/wl/xdg"
+)
+
+func TestWindow(t *testing.T) {
+	s := wltest.NewServer(t)
+	d, err := Connect("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Disconnect()
+
+	w, err := d.NewWindow(64, 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := s.Next("xdg_toplevel.set_title"); r.Args[0] != "Hello!" {
+		t.Errorf("unexpected title %q", r.Args[0])
+	}
+	top := s.Next("xdg_surface.get_toplevel").Args[0].(*wltest.Object)
+	if r := s.Next("wl_shm.create_pool"); r.Args[2] != int32(64*48*4) {
+		t.Errorf("unexpected pool size %v", r.Args[2])
+	}
+
+	// the initial configuration follows the first commit
+	s.Next("xdg_surface.ack_configure")
+	serial := s.Configure(top, 640, 480, xdg.ToplevelStateActivated)
+	if ack := s.Next("xdg_surface.ack_configure"); ack.Args[0] != serial {
+		t.Errorf("acknowledged serial %v, expected %d", ack.Args[0], serial)
+	}
+
+	w.Dispose()
+	s.Next("wl_buffer.destroy")
+}
@@ -30,8 +30,6 @@ func main() {
 		log.Println(http.ListenAndServe("localhost:6060", nil))
 	}()
 
-	exitChan := make(chan bool, 10)
-
 	if flag.NArg() == 0 {
 		log.Fatalf("usage: %s imagefile", os.Args[0])
 	}
@@ -55,23 +53,19 @@ func main() {
 		log.Fatal(err)
 	}
 
-	display.Keyboard().AddKeyHandler(quitter{exitChan})
+	keys, cancel := wl.EventChan(display.Keyboard().OnKey, 10)
 
 	window.Draw(img)
 
-	<-exitChan
+	// quit on q
+	for ev := range keys {
+		if ev.Key == 16 {
+			break
+		}
+	}
+	cancel()
 
 	log.Print("Loop finished")
 	window.Dispose()
 	display.Disconnect()
 }
-
-type quitter struct {
-	ch chan bool
-}
-
-func (q quitter) HandleKeyboardKey(ev wl.KeyboardKeyEvent) {
-	if ev.Key == 16 {
-		q.ch <- true
-	}
-}
@@ -85,6 +85,11 @@ func (d *Display) NewWindow(width, height int32) (*Window, error) {
 		d.registerWindow(w)
 	}
 
+	err = d.Context().Flush()
+	if err != nil {
+		return nil, fmt.Errorf("Flush failed: %s", err)
+	}
+
 	return w, nil
 }
 
@@ -102,6 +107,7 @@ func (w *Window) Dispose() {
 	}
 	w.surface.Destroy()
 	w.buffer.Destroy()
+	w.display.Context().Flush()
 	syscall.Munmap(w.data)
 	w.display.unregisterWindow(w)
 }
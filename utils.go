@@ -5,17 +5,36 @@ import (
 	"unsafe"
 )
 
-var (
-	order binary.ByteOrder
-)
+// Order is the byte order of the wire format, which is the native
+// one.
+var Order binary.ByteOrder
 
 func init() {
 	var x uint32 = 0x01020304
 	if *(*byte)(unsafe.Pointer(&x)) == 0x01 {
-		order = binary.BigEndian
+		Order = binary.BigEndian
 	} else {
-		order = binary.LittleEndian
+		Order = binary.LittleEndian
+	}
+}
+
+// Uint32s decodes an array argument holding uint32 values, such as the
+// keys of wl_keyboard.enter.  A trailing partial value is ignored.
+func Uint32s(array []byte) []uint32 {
+	values := make([]uint32, len(array)/4)
+	for i := range values {
+		values[i] = Order.Uint32(array[4*i:])
+	}
+	return values
+}
+
+// Uint32Array encodes values as an array argument.
+func Uint32Array(values []uint32) []byte {
+	array := make([]byte, 4*len(values))
+	for i, v := range values {
+		Order.PutUint32(array[4*i:], v)
 	}
+	return array
 }
 
 // from https://golang.org/src/math/unsafe.go
@@ -33,3 +33,58 @@ func TestReverse(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestFixedRange(t *testing.T) {
+	for _, test := range []struct {
+		fixed int32
+		float float64
+	}{
+		{0, 0},
+		{1, 1.0 / 256},
+		{-1, -1.0 / 256},
+		{256, 1},
+		{-256, -1},
+		{math.MaxInt32, 8388607.99609375},
+		{math.MinInt32, -8388608},
+		{math.MinInt32 + 1, -8388607.99609375},
+	} {
+		if got := fixedToFloat64(test.fixed); got != test.float {
+			t.Errorf("fixedToFloat64(%#x) = %v, expected %v", test.fixed, got, test.float)
+		}
+		if got := float64ToFixed(test.float); got != test.fixed {
+			t.Errorf("float64ToFixed(%v) = %#x, expected %#x", test.float, got, test.fixed)
+		}
+	}
+}
+
+func TestFixedRounding(t *testing.T) {
+	for _, test := range []struct {
+		float float64
+		fixed Fixed
+	}{
+		{0.3, 77},
+		{-0.3, -77},
+		{1.0 / 1024, 0},
+		{3.0 / 1024, 1},
+		{8388607.998, math.MaxInt32},
+	} {
+		if got := FixedFromFloat64(test.float); got != test.fixed {
+			t.Errorf("FixedFromFloat64(%v) = %d, expected %d", test.float, got, test.fixed)
+		}
+	}
+}
+
+func TestFixedInt(t *testing.T) {
+	for _, v := range []int{0, 1, -1, 1920, -8388608, 8388607} {
+		f := FixedFromInt(v)
+		if f.Int() != v || f.Float64() != float64(v) {
+			t.Errorf("FixedFromInt(%d) = %v, back to %d", v, f, f.Int())
+		}
+	}
+	if got := FixedFromFloat64(-2.75).Int(); got != -2 {
+		t.Errorf("-2.75 truncated to %d", got)
+	}
+	if got := FixedFromFloat64(-2.75).String(); got != "-2.75" {
+		t.Errorf("-2.75 formatted as %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package wl
+
+import (
+	"strings"
+	"testing"
+)
+
+type nameRecorder struct {
+	names []string
+}
+
+func (r *nameRecorder) HandleSeatName(ev SeatNameEvent) {
+	r.names = append(r.names, ev.Name)
+}
+
+func bindSeat(t *testing.T, c *Context, version uint32) *Seat {
+	display := NewDisplay(c)
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seat := NewSeat(c)
+	if err := registry.Bind(1, "wl_seat", version, seat); err != nil {
+		t.Fatal(err)
+	}
+	return seat
+}
+
+func TestRequestVersion(t *testing.T) {
+	c := newTestContext(t)
+	seat := bindSeat(t, c, 4)
+	if seat.Version() != 4 {
+		t.Fatalf("bound at version %d", seat.Version())
+	}
+
+	pointer, err := seat.GetPointer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pointer.Version() != 4 {
+		t.Errorf("pointer created at version %d", pointer.Version())
+	}
+	queued := len(c.out)
+	err = seat.Release()
+	if err == nil || !strings.Contains(err.Error(), "wl_seat.release needs version 5") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(c.out) != queued {
+		t.Error("the request was sent anyway")
+	}
+}
+
+func TestCreatedVersion(t *testing.T) {
+	c := newTestContext(t)
+	display := NewDisplay(c)
+	registry, _ := display.GetRegistry()
+	comp := NewCompositor(c)
+	if err := registry.Bind(1, "wl_compositor", 4, comp); err != nil {
+		t.Fatal(err)
+	}
+	// like libwayland, new objects take the version of their
+	// parent even beyond the version of their own interface
+	region, err := comp.CreateRegion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if region.Version() != 4 {
+		t.Errorf("region created at version %d", region.Version())
+	}
+}
+
+func TestBindVersion(t *testing.T) {
+	c := newTestContext(t)
+	display := NewDisplay(c)
+	registry, _ := display.GetRegistry()
+	seat := NewSeat(c)
+	id := seat.Id()
+	err := registry.Bind(1, "wl_seat", SeatInterface.Version+1, seat)
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// the server never saw the id, so the next object gets it
+	if c.alive(seat) {
+		t.Error("the unbound seat is still registered")
+	}
+	if next := NewSeat(c); next.Id() != id {
+		t.Errorf("got id %d, expected %d", next.Id(), id)
+	}
+}
+
+func TestEventVersion(t *testing.T) {
+	conn, peer := socketpair(t)
+	defer peer.Close()
+	c := newContext(conn)
+	defer conn.Close()
+	seat := bindSeat(t, c, 1)
+	rec := new(nameRecorder)
+	seat.AddNameHandler(rec)
+
+	// wl_seat.name only exists since version 2
+	msg := uint32Data(uint32(seat.Id()), 20<<16|1, 5)
+	msg = append(msg, "seat\x00\x00\x00\x00"...)
+	if _, err := peer.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.readEvents(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DispatchPending(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.names) != 0 {
+		t.Errorf("dispatched %v", rec.names)
+	}
+
+	seat.SetVersion(2)
+	peer.Write(msg)
+	c.readEvents()
+	c.DispatchPending()
+	if len(rec.names) != 1 || rec.names[0] != "seat" {
+		t.Errorf("dispatched %v", rec.names)
+	}
+}
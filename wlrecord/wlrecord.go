@@ -0,0 +1,205 @@
+// Package wlrecord reads and writes recordings of Wayland sessions.
+//
+// A recording is the byte stream a client exchanged with its server,
+// in the chunks it was written and read in, along with a description of
+// the file descriptors that travelled with each chunk.  wl.Record makes
+// a client write one; wltest.Replay plays one back to a client.
+//
+// The format is a magic string followed by records, each made of a
+// direction byte, the time since the start of the recording in
+// microseconds, the data, and the fds, with all numbers as uvarints:
+//
+//	record = dir time len data nfds { kind mode size }
+package wlrecord
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const magic = "WLREC1\n"
+
+// A Direction tells which side sent a chunk.
+type Direction byte
+
+const (
+	ClientToServer Direction = '>'
+	ServerToClient Direction = '<'
+)
+
+func (d Direction) String() string {
+	switch d {
+	case ClientToServer:
+		return "client->server"
+	case ServerToClient:
+		return "server->client"
+	}
+	return fmt.Sprintf("Direction(%d)", byte(d))
+}
+
+// Kinds of file descriptors.
+const (
+	KindFile   = 'f'
+	KindPipe   = 'p'
+	KindSocket = 's'
+	KindOther  = '?'
+)
+
+// An Fd describes a file descriptor sent along with a chunk.  The
+// contents are not recorded.
+type Fd struct {
+	Kind byte
+	// Mode is the access mode, syscall.O_RDONLY, O_WRONLY or O_RDWR
+	Mode int
+	// Size is the size of a regular file
+	Size int64
+}
+
+// DescribeFd returns the description of an open file descriptor.
+func DescribeFd(fd int) Fd {
+	var d Fd
+	var st syscall.Stat_t
+	if err := syscall.Fstat(fd, &st); err != nil {
+		d.Kind = KindOther
+		return d
+	}
+	switch st.Mode & syscall.S_IFMT {
+	case syscall.S_IFREG:
+		d.Kind = KindFile
+		d.Size = st.Size
+	case syscall.S_IFIFO:
+		d.Kind = KindPipe
+	case syscall.S_IFSOCK:
+		d.Kind = KindSocket
+	default:
+		d.Kind = KindOther
+	}
+	if flags, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(fd), syscall.F_GETFL, 0); errno == 0 {
+		d.Mode = int(flags) & syscall.O_ACCMODE
+	}
+	return d
+}
+
+// A Record is one chunk of a session.
+type Record struct {
+	Dir  Direction
+	Time time.Duration
+	Data []byte
+	Fds  []Fd
+}
+
+// A Writer writes a recording.  It is safe for concurrent use.
+type Writer struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	err   error
+}
+
+// NewWriter starts a recording on w.
+func NewWriter(w io.Writer) *Writer {
+	rw := &Writer{w: w, start: time.Now()}
+	_, rw.err = io.WriteString(w, magic)
+	return rw
+}
+
+// Write records a chunk of data and the fds sent with it.  Once a write
+// fails, all later calls return the same error.
+func (w *Writer) Write(dir Direction, data []byte, fds []int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	buf := make([]byte, 0, 1+4*binary.MaxVarintLen64+len(data)+len(fds)*(2+binary.MaxVarintLen64))
+	buf = append(buf, byte(dir))
+	buf = appendUvarint(buf, uint64(time.Since(w.start)/time.Microsecond))
+	buf = appendUvarint(buf, uint64(len(data)))
+	buf = append(buf, data...)
+	buf = appendUvarint(buf, uint64(len(fds)))
+	for _, fd := range fds {
+		d := DescribeFd(fd)
+		buf = append(buf, d.Kind, byte(d.Mode))
+		buf = appendUvarint(buf, uint64(d.Size))
+	}
+	_, w.err = w.w.Write(buf)
+	return w.err
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+// ErrFormat is returned when the input is not a valid recording.
+var ErrFormat = errors.New("wlrecord: invalid recording")
+
+// A Reader reads a recording.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader checks the header of a recording and returns a reader for
+// its records.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	head := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, head); err != nil || string(head) != magic {
+		return nil, ErrFormat
+	}
+	return &Reader{r: br}, nil
+}
+
+// Next returns the next record, or io.EOF at the end of the recording.
+func (r *Reader) Next() (*Record, error) {
+	dir, err := r.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if Direction(dir) != ClientToServer && Direction(dir) != ServerToClient {
+		return nil, ErrFormat
+	}
+	rec := &Record{Dir: Direction(dir)}
+	t, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	rec.Time = time.Duration(t) * time.Microsecond
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil || n > 1<<24 {
+		return nil, ErrFormat
+	}
+	rec.Data = make([]byte, n)
+	if _, err := io.ReadFull(r.r, rec.Data); err != nil {
+		return nil, ErrFormat
+	}
+	nfds, err := binary.ReadUvarint(r.r)
+	if err != nil || nfds > 255 {
+		return nil, ErrFormat
+	}
+	for i := uint64(0); i < nfds; i++ {
+		var d Fd
+		var mode byte
+		if d.Kind, err = r.r.ReadByte(); err != nil {
+			return nil, ErrFormat
+		}
+		if mode, err = r.r.ReadByte(); err != nil {
+			return nil, ErrFormat
+		}
+		d.Mode = int(mode)
+		size, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			return nil, ErrFormat
+		}
+		d.Size = int64(size)
+		rec.Fds = append(rec.Fds, d)
+	}
+	return rec, nil
+}
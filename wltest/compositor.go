@@ -0,0 +1,169 @@
+package wltest
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+	"github.com/dkolbly/wl/xdg"
+)
+
+// surface is the state of a wl_surface.
+type surface struct {
+	pending *Object
+	buffer  *Object
+	frames  []*Object
+	role    *Object
+}
+
+// xdgSurface is the state of an xdg_surface.
+type xdgSurface struct {
+	surface    *Object
+	toplevel   *Object
+	configured bool
+}
+
+// builtins implement the requests that need a response for a client
+// to get going.
+var builtins = map[string]func(s *Server, r *Request){
+	"wl_display.sync": func(s *Server, r *Request) {
+		cb := r.Args[0].(*Object)
+		cb.Send("done", s.NextSerial())
+		cb.Client.Destroy(cb)
+	},
+	"wl_display.get_registry": func(s *Server, r *Request) {
+		registry := r.Args[0].(*Object)
+		s.mu.Lock()
+		globals := append([]*Global(nil), s.globals...)
+		s.mu.Unlock()
+		for _, g := range globals {
+			registry.Send("global", g.Name, g.Interface.Name, g.Version)
+		}
+	},
+	"wl_registry.bind": func(s *Server, r *Request) {
+		name, obj := r.Args[0].(uint32), r.Args[1].(*Object)
+		s.mu.Lock()
+		var global *Global
+		if name >= 1 && int(name) <= len(s.globals) {
+			global = s.globals[name-1]
+		}
+		s.mu.Unlock()
+		if global == nil || global.Interface != obj.Interface || obj.Version == 0 || obj.Version > global.Version {
+			r.Object.PostError(uint32(wl.DisplayErrorInvalidObject),
+				fmt.Sprintf("invalid global %s (%d)", obj.Interface.Name, name))
+			return
+		}
+		switch obj.Interface {
+		case wl.SeatInterface:
+			obj.Send("capabilities", uint32(s.SeatCapabilities))
+			if obj.Version >= 2 {
+				obj.Send("name", "seat0")
+			}
+		case wl.ShmInterface:
+			obj.Send("format", uint32(wl.ShmFormatArgb8888))
+			obj.Send("format", uint32(wl.ShmFormatXrgb8888))
+		}
+	},
+	"wl_compositor.create_surface": func(s *Server, r *Request) {
+		s.mu.Lock()
+		r.Args[0].(*Object).data = new(surface)
+		s.mu.Unlock()
+	},
+	"wl_surface.attach": func(s *Server, r *Request) {
+		s.mu.Lock()
+		r.Object.data.(*surface).pending, _ = r.Args[0].(*Object)
+		s.mu.Unlock()
+	},
+	"wl_surface.frame": func(s *Server, r *Request) {
+		s.mu.Lock()
+		sf := r.Object.data.(*surface)
+		sf.frames = append(sf.frames, r.Args[0].(*Object))
+		s.mu.Unlock()
+	},
+	"wl_surface.commit": func(s *Server, r *Request) {
+		s.mu.Lock()
+		sf := r.Object.data.(*surface)
+		sf.buffer = sf.pending
+		frames := sf.frames
+		sf.frames = nil
+		var toplevel *Object
+		if sf.role != nil {
+			xs := sf.role.data.(*xdgSurface)
+			if !xs.configured && xs.toplevel != nil {
+				toplevel = xs.toplevel
+			}
+		}
+		s.mu.Unlock()
+
+		// there is no repainting, so frames are done right away
+		ms := uint32(time.Now().UnixNano() / int64(time.Millisecond))
+		for _, cb := range frames {
+			cb.Send("done", ms)
+			cb.Client.Destroy(cb)
+		}
+		// the initial commit of a toplevel asks for its configuration
+		if toplevel != nil {
+			s.Configure(toplevel, 0, 0)
+		}
+	},
+	"wl_shm.create_pool": func(s *Server, r *Request) {
+		s.mu.Lock()
+		r.Args[0].(*Object).data = r.Args[1]
+		s.mu.Unlock()
+	},
+	"wl_shm_pool.destroy": func(s *Server, r *Request) {
+		s.mu.Lock()
+		f := r.Object.data.(*os.File)
+		s.mu.Unlock()
+		f.Close()
+	},
+	"xdg_wm_base.get_xdg_surface": func(s *Server, r *Request) {
+		xs, surf := r.Args[0].(*Object), r.Args[1].(*Object)
+		s.mu.Lock()
+		xs.data = &xdgSurface{surface: surf}
+		surf.data.(*surface).role = xs
+		s.mu.Unlock()
+	},
+	"xdg_surface.get_toplevel": func(s *Server, r *Request) {
+		top := r.Args[0].(*Object)
+		s.mu.Lock()
+		r.Object.data.(*xdgSurface).toplevel = top
+		top.data = r.Object
+		s.mu.Unlock()
+	},
+}
+
+// dispatch runs the built-in and registered handlers of a request.
+func (s *Server) dispatch(r *Request) {
+	name := r.Name()
+	if h := builtins[name]; h != nil {
+		h(s, r)
+	}
+	s.mu.Lock()
+	handlers := s.handlers[name]
+	s.mu.Unlock()
+	for _, h := range handlers {
+		h(r)
+	}
+}
+
+// Configure sends xdg_toplevel.configure with the given size and
+// states to an xdg_toplevel, followed by xdg_surface.configure, and
+// returns the serial the client is expected to acknowledge.
+func (s *Server) Configure(toplevel *Object, width, height int32, states ...xdg.ToplevelState) uint32 {
+	s.mu.Lock()
+	xs := toplevel.data.(*Object)
+	xs.data.(*xdgSurface).configured = true
+	s.mu.Unlock()
+
+	var e wire.Encoder
+	for _, state := range states {
+		e.PutUint32(uint32(state))
+	}
+	serial := s.NextSerial()
+	toplevel.Send("configure", width, height, e.Data)
+	xs.Send("configure", serial)
+	return serial
+}
@@ -0,0 +1,74 @@
+package wltest
+
+import (
+	"fmt"
+
+	"github.com/dkolbly/wl"
+)
+
+// An Object is a protocol object of a client.
+type Object struct {
+	Client    *Client
+	Id        uint32
+	Interface *wl.Interface
+	Version   uint32
+
+	// data holds the state kept by the built-in request handling;
+	// guarded by the server
+	data      interface{}
+	destroyed bool
+}
+
+// Send sends the event with the given name to the client.  Arguments
+// are passed as int32, uint32, wl.Fixed, string, *Object for object
+// and new_id, []byte for arrays and *os.File, uintptr or int for file
+// descriptors.
+func (o *Object) Send(event string, args ...interface{}) error {
+	for i := range o.Interface.Events {
+		if o.Interface.Events[i].Name == event {
+			return o.Client.writeEvent(o, uint32(i), args...)
+		}
+	}
+	return fmt.Errorf("%s has no event %s", o.Interface.Name, event)
+}
+
+// PostError sends a wl_display.error event about the object and
+// disconnects the client, like a compositor does on a protocol error.
+func (o *Object) PostError(code uint32, msg string) {
+	c := o.Client
+	c.srv.mu.Lock()
+	err := c.postErrorLocked(o, code, msg)
+	c.srv.mu.Unlock()
+	c.fail(err)
+	c.conn.CloseWrite()
+}
+
+// Destroyed reports whether the client has destroyed the object.
+func (o *Object) Destroyed() bool {
+	o.Client.srv.mu.Lock()
+	defer o.Client.srv.mu.Unlock()
+	return o.destroyed
+}
+
+func (o *Object) String() string {
+	return fmt.Sprintf("%s@%d", o.Interface.Name, o.Id)
+}
+
+// A Request is a request received from a client.
+type Request struct {
+	Object  *Object
+	Message *wl.Message
+	// Args holds the arguments, typed as described for Object.Send;
+	// file descriptors are received as *os.File.
+	Args []interface{}
+}
+
+// Name returns the name of the request including its interface, such
+// as "wl_surface.commit".
+func (r *Request) Name() string {
+	return r.Object.Interface.Name + "." + r.Message.Name
+}
+
+func (r *Request) String() string {
+	return fmt.Sprintf("%s.%s%v", r.Object, r.Message.Name, r.Args)
+}
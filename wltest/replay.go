@@ -0,0 +1,284 @@
+package wltest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+	"github.com/dkolbly/wl/wlrecord"
+)
+
+// A Replayer plays a recording made with wl.Record back to a client,
+// standing in for the server it was recorded against.  The events are
+// sent as they were recorded, each once the client has sent everything
+// it sent before them, and the requests of the client are checked
+// against the recording byte for byte.  File descriptors are replaced
+// by files, pipes or sockets of the same kind; their contents are not
+// part of the recording.
+//
+//	r := wltest.Replay(t, f)
+//	d, err := ui.Connect("")
+//	...
+//	if err := r.Wait(); err != nil {
+//		t.Fatal(err)
+//	}
+type Replayer struct {
+	ln   *net.UnixListener
+	name string
+	dir  string
+
+	mu     sync.Mutex
+	conn   *net.UnixConn
+	closed bool
+
+	done chan struct{}
+	err  error
+
+	// keep holds the far ends of the pipes and sockets sent
+	keep []*os.File
+}
+
+// Replay reads a recording and waits for a client to play it to, with
+// XDG_RUNTIME_DIR and WAYLAND_DISPLAY set like NewServer does.  The
+// client is disconnected when the test ends.
+func Replay(t testing.TB, recording io.Reader) *Replayer {
+	rd, err := wlrecord.NewReader(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []*wlrecord.Record
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+
+	ln, name := listen(t)
+	r := &Replayer{
+		ln:   ln,
+		name: name,
+		dir:  t.TempDir(),
+		done: make(chan struct{}),
+	}
+	go r.run(records)
+	t.Cleanup(r.Close)
+	return r
+}
+
+// Display returns the name of the display socket, relative to
+// XDG_RUNTIME_DIR.
+func (r *Replayer) Display() string {
+	return r.name
+}
+
+// Wait waits until the whole recording has been played, or the client
+// strayed from it, and returns the first difference found.
+func (r *Replayer) Wait() error {
+	<-r.done
+	return r.err
+}
+
+// Close stops listening and disconnects the client.  It is called when
+// the test ends.
+func (r *Replayer) Close() {
+	r.mu.Lock()
+	r.closed = true
+	conn := r.conn
+	r.mu.Unlock()
+
+	r.ln.Close()
+	if conn != nil {
+		conn.Close()
+	}
+	<-r.done
+	for _, f := range r.keep {
+		f.Close()
+	}
+	r.keep = nil
+}
+
+func (r *Replayer) run(records []*wlrecord.Record) {
+	defer close(r.done)
+	r.ln.SetDeadline(time.Now().Add(Timeout))
+	conn, err := r.ln.AcceptUnix()
+	if err != nil {
+		r.err = fmt.Errorf("wltest: no client to replay to: %v", err)
+		return
+	}
+	r.ln.Close()
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		conn.Close()
+		r.err = fmt.Errorf("wltest: replay closed")
+		return
+	}
+	r.conn = conn
+	r.mu.Unlock()
+
+	var in []byte
+	for i, rec := range records {
+		switch rec.Dir {
+		case wlrecord.ServerToClient:
+			fds, err := r.substitute(rec.Fds)
+			if err == nil {
+				_, _, err = conn.WriteMsgUnix(rec.Data, syscall.UnixRights(fds...), nil)
+				for _, fd := range fds {
+					syscall.Close(fd)
+				}
+			}
+			if err != nil {
+				r.err = fmt.Errorf("wltest: record %d: %v", i, err)
+				return
+			}
+
+		case wlrecord.ClientToServer:
+			// check the requests as they come, to report a
+			// difference rather than wait for more
+			for {
+				n := len(in)
+				if n > len(rec.Data) {
+					n = len(rec.Data)
+				}
+				if !bytes.Equal(in[:n], rec.Data[:n]) {
+					r.err = fmt.Errorf("wltest: record %d: %s", i, describeDiff(rec.Data, in[:n]))
+					return
+				}
+				if n == len(rec.Data) {
+					break
+				}
+				buf, err := readChunk(conn)
+				if err != nil {
+					r.err = fmt.Errorf("wltest: record %d: %v", i, err)
+					return
+				}
+				in = append(in, buf...)
+			}
+			in = in[len(rec.Data):]
+		}
+	}
+}
+
+// readChunk reads what the client sent, closing the fds that came
+// with it.
+func readChunk(conn *net.UnixConn) ([]byte, error) {
+	buf := make([]byte, 4096)
+	oob := make([]byte, syscall.CmsgSpace(wire.MaxFds*4))
+	conn.SetReadDeadline(time.Now().Add(Timeout))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if n == 0 && err == nil {
+		err = io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if msgs, err := syscall.ParseSocketControlMessage(oob[:oobn]); err == nil {
+		for _, msg := range msgs {
+			fds, _ := syscall.ParseUnixRights(&msg)
+			for _, fd := range fds {
+				syscall.Close(fd)
+			}
+		}
+	}
+	return buf[:n], nil
+}
+
+// substitute opens a file descriptor standing in for each recorded
+// one.  The caller closes them once they are sent.
+func (r *Replayer) substitute(descs []wlrecord.Fd) ([]int, error) {
+	var fds []int
+	for _, d := range descs {
+		var f *os.File
+		var err error
+		switch d.Kind {
+		case wlrecord.KindFile:
+			f, err = ioutil.TempFile(r.dir, "fd")
+			if err == nil {
+				err = f.Truncate(d.Size)
+			}
+		case wlrecord.KindPipe:
+			var rd, wr *os.File
+			rd, wr, err = os.Pipe()
+			if err == nil {
+				if d.Mode == syscall.O_WRONLY {
+					f = wr
+					r.keep = append(r.keep, rd)
+				} else {
+					f = rd
+					r.keep = append(r.keep, wr)
+				}
+			}
+		case wlrecord.KindSocket:
+			var pair [2]int
+			pair, err = syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+			if err == nil {
+				f = os.NewFile(uintptr(pair[0]), "socket")
+				r.keep = append(r.keep, os.NewFile(uintptr(pair[1]), "socket"))
+			}
+		default:
+			f, err = os.OpenFile(os.DevNull, d.Mode, 0)
+		}
+		if err != nil {
+			if f != nil {
+				f.Close()
+			}
+			for _, fd := range fds {
+				syscall.Close(fd)
+			}
+			return nil, err
+		}
+		// the descriptor outlives f
+		fd, err := syscall.Dup(int(f.Fd()))
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		fds = append(fds, fd)
+	}
+	return fds, nil
+}
+
+// describeDiff explains where the requests of a client differ from
+// the recording, by the header of the first message that differs.  got
+// may be shorter than want.
+func describeDiff(want, got []byte) string {
+	off := 0
+	for off+8 <= len(want) {
+		size := int(wl.Order.Uint32(want[off+4:]) >> 16)
+		if size < 8 || off+size > len(want) {
+			break
+		}
+		end := off + size
+		if end > len(got) {
+			end = len(got)
+		}
+		if !bytes.Equal(want[off:end], got[off:end]) {
+			return fmt.Sprintf("client sent %s, recording has %s", header(got[off:]), header(want[off:]))
+		}
+		off += size
+	}
+	return fmt.Sprintf("client requests differ from the recording at offset %d", off)
+}
+
+func header(msg []byte) string {
+	if len(msg) < 8 {
+		return "a partial message"
+	}
+	id := wl.Order.Uint32(msg)
+	word := wl.Order.Uint32(msg[4:])
+	return fmt.Sprintf("request %d of object %d (%d bytes)", word&0xffff, id, word>>16)
+}
@@ -0,0 +1,141 @@
+package wltest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/wlrecord"
+)
+
+type keymapRecorder struct {
+	size uint32
+	fd   uintptr
+}
+
+func (r *keymapRecorder) HandleKeyboardKeymap(ev wl.KeyboardKeymapEvent) {
+	r.size = ev.Size
+	r.fd = ev.Fd
+}
+
+// session binds a keyboard and waits for its keymap, which comes with
+// an fd, then creates a surface, moving on after each roundtrip.
+func session(t *testing.T, frames int, opts ...wl.Option) *keymapRecorder {
+	display, err := wl.Connect("", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer display.Context().Close()
+	c := display.Context()
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	seat := wl.NewSeat(c)
+	registry.Bind(4, "wl_seat", 5, seat)
+	keyboard, _ := seat.GetKeyboard()
+	km := new(keymapRecorder)
+	keyboard.AddKeymapHandler(km)
+	compositor := wl.NewCompositor(c)
+	registry.Bind(1, "wl_compositor", 4, compositor)
+	surface, _ := compositor.CreateSurface()
+	for i := 0; i < frames; i++ {
+		surface.Commit()
+		if err := display.Roundtrip(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if km.fd != ^uintptr(0) {
+		os.NewFile(km.fd, "keymap").Close()
+	}
+	return km
+}
+
+func record(t *testing.T, frames int) []byte {
+	s := NewServer(t)
+	s.Handle("wl_seat.get_keyboard", func(r *Request) {
+		f, err := ioutil.TempFile(t.TempDir(), "keymap")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer f.Close()
+		f.WriteString("xkb_keymap {};")
+		r.Args[0].(*Object).Send("keymap", uint32(wl.KeyboardKeymapFormatXkbV1), f, uint32(14))
+	})
+	var buf bytes.Buffer
+	session(t, frames, wl.Record(&buf))
+	s.Close()
+	return buf.Bytes()
+}
+
+func TestRecording(t *testing.T) {
+	data := record(t, 1)
+	rd, err := wlrecord.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fds []wlrecord.Fd
+	counts := make(map[wlrecord.Direction]int)
+	for {
+		rec, err := rd.Next()
+		if err != nil {
+			break
+		}
+		counts[rec.Dir] += len(rec.Data)
+		fds = append(fds, rec.Fds...)
+	}
+	if counts[wlrecord.ClientToServer] == 0 || counts[wlrecord.ServerToClient] == 0 {
+		t.Errorf("unexpected traffic %v", counts)
+	}
+	if len(fds) != 1 || fds[0].Kind != wlrecord.KindFile || fds[0].Size != 14 {
+		t.Errorf("unexpected fds %+v", fds)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	data := record(t, 2)
+
+	r := Replay(t, bytes.NewReader(data))
+	km := session(t, 2)
+	if err := r.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if km.size != 14 {
+		t.Errorf("keymap of size %d", km.size)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	data := record(t, 2)
+
+	r := Replay(t, bytes.NewReader(data))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		display, err := wl.Connect("")
+		if err != nil {
+			return
+		}
+		defer display.Context().Close()
+		display.GetRegistry()
+		display.Sync()
+		// not in the recording
+		display.GetRegistry()
+		display.Context().Flush()
+		<-display.Context().Done()
+	}()
+	err := r.Wait()
+	r.Close()
+	<-done
+	if err == nil || !strings.Contains(err.Error(), "request 1 of object 1") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
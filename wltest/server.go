@@ -0,0 +1,468 @@
+// Package wltest provides a fake compositor for testing Wayland
+// clients without a display.
+//
+// A Server listens on a socket in a temporary XDG_RUNTIME_DIR and
+// points WAYLAND_DISPLAY at it for the duration of the test, so that
+// wl.Connect("") and ui.Connect("") reach it.  It implements enough of
+// the core protocol and xdg-shell to get a window on screen, records
+// every request it receives, and lets the test send arbitrary events:
+//
+//	s := wltest.NewServer(t)
+//	d, err := ui.Connect("")
+//	...
+//	top := s.Next("xdg_surface.get_toplevel").Args[0].(*wltest.Object)
+//	s.Configure(top, 640, 480)
+//	s.Next("xdg_surface.ack_configure")
+package wltest
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+	"github.com/dkolbly/wl/xdg"
+)
+
+// Timeout bounds how long Next waits for a request.
+var Timeout = 5 * time.Second
+
+// serverIdStart is the first id of objects created by the server.
+const serverIdStart = 0xff000000
+
+// A Global is an object advertised through wl_registry.
+type Global struct {
+	Name      uint32
+	Interface *wl.Interface
+	Version   uint32
+}
+
+// A RequestHandler is called for each request of a kind, after the
+// built-in handling.
+type RequestHandler func(r *Request)
+
+// Server is a fake compositor.
+type Server struct {
+	t    testing.TB
+	ln   *net.UnixListener
+	wg   sync.WaitGroup
+	name string
+
+	// SeatCapabilities is sent to clients binding wl_seat.
+	SeatCapabilities wl.SeatCapability
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	clients  []*Client
+	globals  []*Global
+	handlers map[string][]RequestHandler
+	requests []*Request
+	seen     map[string]int
+	serial   uint32
+	closed   bool
+}
+
+// NewServer starts a fake compositor advertising wl_compositor,
+// wl_subcompositor, wl_shm, wl_seat, wl_data_device_manager, wl_shell
+// and xdg_wm_base, and sets XDG_RUNTIME_DIR and WAYLAND_DISPLAY to
+// reach it.  The server is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	ln, name := listen(t)
+	s := &Server{
+		t:                t,
+		ln:               ln,
+		name:             name,
+		SeatCapabilities: wl.SeatCapabilityPointer | wl.SeatCapabilityKeyboard,
+		handlers:         make(map[string][]RequestHandler),
+		seen:             make(map[string]int),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	s.AddGlobal(wl.CompositorInterface, 4)
+	s.AddGlobal(wl.SubcompositorInterface, 1)
+	s.AddGlobal(wl.ShmInterface, 1)
+	s.AddGlobal(wl.SeatInterface, 5)
+	s.AddGlobal(wl.DataDeviceManagerInterface, 3)
+	s.AddGlobal(wl.ShellInterface, 1)
+	s.AddGlobal(xdg.WmBaseInterface, 2)
+
+	s.wg.Add(1)
+	go s.accept()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// listen creates the display socket in a temporary XDG_RUNTIME_DIR
+// and points WAYLAND_DISPLAY at it.
+func listen(t testing.TB) (*net.UnixListener, string) {
+	dir := t.TempDir()
+	name := "wayland-test"
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: filepath.Join(dir, name), Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv("WAYLAND_DISPLAY", name)
+	return ln, name
+}
+
+// Display returns the name of the display socket, relative to
+// XDG_RUNTIME_DIR.
+func (s *Server) Display() string {
+	return s.name
+}
+
+// AddGlobal advertises an interface to clients binding wl_registry
+// from now on, and returns the new global.
+func (s *Server) AddGlobal(iface *wl.Interface, version uint32) *Global {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := &Global{
+		Name:      uint32(len(s.globals) + 1),
+		Interface: iface,
+		Version:   version,
+	}
+	s.globals = append(s.globals, g)
+	return g
+}
+
+// Handle registers h to be called for every request with the given
+// name, such as "wl_surface.commit".  Handlers run on the goroutine
+// serving the client, after the built-in handling of the request.
+func (s *Server) Handle(name string, h RequestHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[name] = append(s.handlers[name], h)
+}
+
+// Clients returns the clients currently connected.
+func (s *Server) Clients() []*Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Client(nil), s.clients...)
+}
+
+// Requests returns all requests received so far, in order.
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Request(nil), s.requests...)
+}
+
+// Next waits for the next request with the given name, such as
+// "xdg_surface.ack_configure", that has not been returned by Next yet.
+// It fails the test if none arrives within Timeout.
+func (s *Server) Next(name string) *Request {
+	s.t.Helper()
+	deadline := time.Now().Add(Timeout)
+	timer := time.AfterFunc(Timeout, func() {
+		s.mu.Lock()
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		for i := s.seen[name]; i < len(s.requests); i++ {
+			if s.requests[i].Name() == name {
+				s.seen[name] = i + 1
+				return s.requests[i]
+			}
+		}
+		if s.closed || time.Now().After(deadline) {
+			s.t.Fatalf("wltest: no %s request received", name)
+		}
+		s.cond.Wait()
+	}
+}
+
+// NextSerial returns a new serial for an event.
+func (s *Server) NextSerial() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serial++
+	return s.serial
+}
+
+// Close disconnects all clients and stops listening.
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	clients := s.clients
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	s.ln.Close()
+	for _, c := range clients {
+		c.conn.Close()
+	}
+	s.wg.Wait()
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.AcceptUnix()
+		if err != nil {
+			return
+		}
+		c := newClient(s, conn)
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.clients = append(s.clients, c)
+		s.cond.Broadcast()
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go c.serve()
+	}
+}
+
+// removeClient forgets a client that has disconnected.
+func (s *Server) removeClient(c *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, other := range s.clients {
+		if other == c {
+			s.clients = append(s.clients[:i], s.clients[i+1:]...)
+			break
+		}
+	}
+	s.cond.Broadcast()
+}
+
+// A Client is a connection to the fake compositor.
+type Client struct {
+	srv  *Server
+	conn *net.UnixConn
+	r    *wire.Reader
+
+	wmu sync.Mutex
+
+	// guarded by srv.mu
+	objects map[uint32]*Object
+	nextId  uint32
+	err     error
+}
+
+func newClient(s *Server, conn *net.UnixConn) *Client {
+	c := &Client{
+		srv:     s,
+		conn:    conn,
+		r:       wire.NewReader(conn),
+		objects: make(map[uint32]*Object),
+		nextId:  serverIdStart,
+	}
+	c.objects[1] = &Object{Client: c, Id: 1, Interface: wl.DisplayInterface, Version: 1}
+	return c
+}
+
+// Err returns the reason the client was disconnected, if it was.
+func (c *Client) Err() error {
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+	return c.err
+}
+
+// Object returns the live object with the given id, or nil.
+func (c *Client) Object(id uint32) *Object {
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+	return c.objects[id]
+}
+
+// Objects returns the live objects of the given interface, such as
+// "wl_pointer".
+func (c *Client) Objects(iface string) []*Object {
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+	var objs []*Object
+	for _, o := range c.objects {
+		if o.Interface.Name == iface {
+			objs = append(objs, o)
+		}
+	}
+	return objs
+}
+
+// NewObject creates a server-side object, to be passed as the new_id
+// argument of an event.
+func (c *Client) NewObject(iface *wl.Interface, version uint32) *Object {
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+	o := &Object{Client: c, Id: c.nextId, Interface: iface, Version: version}
+	c.nextId++
+	c.objects[o.Id] = o
+	return o
+}
+
+func (c *Client) serve() {
+	defer c.srv.wg.Done()
+	defer c.srv.removeClient(c)
+	defer c.conn.Close()
+	defer c.r.Close()
+
+	for {
+		msg, err := c.r.ReadMessage()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		r, err := c.decode(msg)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		c.srv.dispatch(r)
+		if r.Message.Destructor && r.Object.Id < serverIdStart {
+			// the client does not reuse the id before delete_id
+			c.writeEvent(c.Object(1), 1, r.Object.Id)
+		}
+	}
+}
+
+// fail records why the client is being disconnected.
+func (c *Client) fail(err error) {
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// decode parses a request and updates the object table for the
+// objects it creates or destroys.
+func (c *Client) decode(msg *wire.Message) (*Request, error) {
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+
+	obj := c.objects[msg.Id]
+	if obj == nil {
+		return nil, c.postErrorLocked(c.objects[1], uint32(wl.DisplayErrorInvalidObject),
+			fmt.Sprintf("invalid object %d", msg.Id))
+	}
+	if msg.Opcode >= uint32(len(obj.Interface.Requests)) {
+		return nil, c.postErrorLocked(obj, uint32(wl.DisplayErrorInvalidMethod),
+			fmt.Sprintf("invalid method %d, object %s@%d", msg.Opcode, obj.Interface.Name, obj.Id))
+	}
+	m := &obj.Interface.Requests[msg.Opcode]
+	r := &Request{Object: obj, Message: m}
+	d := wire.Decoder{Data: msg.Data}
+	for _, arg := range m.Args {
+		switch arg.Type {
+		case wl.ArgInt:
+			r.Args = append(r.Args, d.Int32())
+		case wl.ArgUint:
+			r.Args = append(r.Args, d.Uint32())
+		case wl.ArgFixed:
+			r.Args = append(r.Args, d.Fixed())
+		case wl.ArgString:
+			r.Args = append(r.Args, d.String())
+		case wl.ArgObject:
+			id := d.Uint32()
+			arg := c.objects[id]
+			if id != 0 && arg == nil {
+				return nil, c.postErrorLocked(c.objects[1], uint32(wl.DisplayErrorInvalidObject),
+					fmt.Sprintf("invalid object %d", id))
+			}
+			r.Args = append(r.Args, arg)
+		case wl.ArgNewId:
+			iface, version := wl.LookupInterface(arg.Interface), obj.Version
+			if arg.Interface == "" {
+				// wl_registry.bind spells out the interface
+				iface = wl.LookupInterface(d.String())
+				version = d.Uint32()
+			}
+			id := d.Uint32()
+			if iface == nil {
+				return nil, c.postErrorLocked(obj, uint32(wl.DisplayErrorInvalidObject),
+					fmt.Sprintf("unknown interface for new object %d", id))
+			}
+			if c.objects[id] != nil || id == 0 || id >= serverIdStart {
+				return nil, c.postErrorLocked(c.objects[1], uint32(wl.DisplayErrorInvalidObject),
+					fmt.Sprintf("invalid new id %d", id))
+			}
+			o := &Object{Client: c, Id: id, Interface: iface, Version: version}
+			c.objects[id] = o
+			r.Args = append(r.Args, o)
+		case wl.ArgArray:
+			r.Args = append(r.Args, d.Array())
+		case wl.ArgFd:
+			fd, ok := c.r.TakeFd()
+			if !ok {
+				return nil, fmt.Errorf("%s@%d.%s: missing file descriptor", obj.Interface.Name, obj.Id, m.Name)
+			}
+			r.Args = append(r.Args, os.NewFile(uintptr(fd), "fd"))
+		}
+	}
+	if d.Err != nil {
+		return nil, c.postErrorLocked(obj, uint32(wl.DisplayErrorInvalidMethod),
+			fmt.Sprintf("%s@%d.%s: %s", obj.Interface.Name, obj.Id, m.Name, d.Err))
+	}
+	if m.Destructor {
+		c.destroyLocked(obj)
+	}
+	c.srv.requests = append(c.srv.requests, r)
+	c.srv.cond.Broadcast()
+	return r, nil
+}
+
+// destroyLocked removes obj from the object table.
+func (c *Client) destroyLocked(obj *Object) {
+	delete(c.objects, obj.Id)
+	obj.destroyed = true
+}
+
+// Destroy removes an object created by the client, as after a
+// destructor request, and sends wl_display.delete_id.
+func (c *Client) Destroy(obj *Object) error {
+	c.srv.mu.Lock()
+	c.destroyLocked(obj)
+	display := c.objects[1]
+	c.srv.mu.Unlock()
+	return c.writeEvent(display, 1, obj.Id)
+}
+
+// postErrorLocked sends wl_display.error and returns it as an error,
+// so that the connection gets closed.
+func (c *Client) postErrorLocked(obj *Object, code uint32, msg string) error {
+	display := c.objects[1]
+	err := fmt.Errorf("protocol error %d: %s", code, msg)
+	c.writeEvent(display, 0, obj, code, msg)
+	return err
+}
+
+// writeEvent sends an event of obj to the client.
+func (c *Client) writeEvent(obj *Object, opcode uint32, args ...interface{}) error {
+	if opcode >= uint32(len(obj.Interface.Events)) {
+		return fmt.Errorf("%s has no event %d", obj.Interface.Name, opcode)
+	}
+	m := &obj.Interface.Events[opcode]
+	if len(args) != len(m.Args) {
+		return fmt.Errorf("%s.%s takes %d arguments, got %d", obj.Interface.Name, m.Name, len(m.Args), len(args))
+	}
+	var e wire.Encoder
+	for i, arg := range m.Args {
+		if err := encodeArg(&e, arg, args[i]); err != nil {
+			return fmt.Errorf("%s.%s: %s", obj.Interface.Name, m.Name, err)
+		}
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	return e.WriteMessage(c.conn, obj.Id, opcode)
+}
@@ -0,0 +1,190 @@
+package wltest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/xdg"
+)
+
+type recorder struct {
+	mu      sync.Mutex
+	globals []string
+	caps    wl.SeatCapability
+	motions []wl.PointerMotionEvent
+	errors  []wl.DisplayErrorEvent
+}
+
+func (r *recorder) HandleRegistryGlobal(ev wl.RegistryGlobalEvent) {
+	r.mu.Lock()
+	r.globals = append(r.globals, ev.Interface)
+	r.mu.Unlock()
+}
+
+func (r *recorder) HandleSeatCapabilities(ev wl.SeatCapabilitiesEvent) {
+	r.mu.Lock()
+	r.caps = ev.Capabilities
+	r.mu.Unlock()
+}
+
+func (r *recorder) HandlePointerMotion(ev wl.PointerMotionEvent) {
+	r.mu.Lock()
+	r.motions = append(r.motions, ev)
+	r.mu.Unlock()
+}
+
+func (r *recorder) HandleDisplayError(ev wl.DisplayErrorEvent) {
+	r.mu.Lock()
+	r.errors = append(r.errors, ev)
+	r.mu.Unlock()
+}
+
+func connect(t *testing.T) (*wl.Display, *wl.Registry, *recorder) {
+	display, err := wl.Connect("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { display.Context().Close() })
+	rec := new(recorder)
+	display.AddErrorHandler(rec)
+	registry, err := display.GetRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry.AddGlobalHandler(rec)
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return display, registry, rec
+}
+
+func TestGlobalsAndInput(t *testing.T) {
+	s := NewServer(t)
+	display, registry, rec := connect(t)
+	if len(rec.globals) != 7 || rec.globals[6] != "xdg_wm_base" {
+		t.Fatalf("unexpected globals %v", rec.globals)
+	}
+
+	seat := wl.NewSeat(display.Context())
+	seat.AddCapabilitiesHandler(rec)
+	if err := registry.Bind(4, "wl_seat", 5, seat); err != nil {
+		t.Fatal(err)
+	}
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.caps != wl.SeatCapabilityPointer|wl.SeatCapabilityKeyboard {
+		t.Errorf("unexpected capabilities %d", rec.caps)
+	}
+
+	pointer, err := seat.GetPointer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pointer.AddMotionHandler(rec)
+	display.Context().Flush()
+	obj := s.Next("wl_seat.get_pointer").Args[0].(*Object)
+	if err := obj.Send("motion", uint32(10), wl.FixedFromFloat64(1.5), wl.FixedFromFloat64(-2.25)); err != nil {
+		t.Fatal(err)
+	}
+	if err := display.Roundtrip(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.motions) != 1 || rec.motions[0] != (wl.PointerMotionEvent{Time: 10, SurfaceX: wl.FixedFromFloat64(1.5), SurfaceY: wl.FixedFromFloat64(-2.25)}) {
+		t.Errorf("unexpected motion events %v", rec.motions)
+	}
+}
+
+func TestSurfaceCommit(t *testing.T) {
+	s := NewServer(t)
+	display, registry, _ := connect(t)
+	c := display.Context()
+	compositor := wl.NewCompositor(c)
+	registry.Bind(1, "wl_compositor", 4, compositor)
+	surface, _ := compositor.CreateSurface()
+	c.Flush()
+
+	s.Next("wl_compositor.create_surface")
+	objs := s.Clients()[0].Objects("wl_compositor")
+	if len(objs) != 1 || objs[0].Version != 4 {
+		t.Errorf("unexpected compositors %v", objs)
+	}
+	if err := surface.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	c.Flush()
+	r := s.Next("wl_surface.commit")
+	if r.Object.Id != uint32(surface.Id()) {
+		t.Errorf("commit for %v", r.Object)
+	}
+}
+
+func TestPostError(t *testing.T) {
+	s := NewServer(t)
+	display, registry, rec := connect(t)
+	compositor := wl.NewCompositor(display.Context())
+	registry.Bind(1, "wl_compositor", 4, compositor)
+	display.Context().Flush()
+
+	obj := s.Next("wl_registry.bind").Args[1].(*Object)
+	obj.PostError(3, "testing")
+	<-display.Context().Done()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.errors) != 1 || rec.errors[0].ObjectId != compositor || rec.errors[0].Code != 3 || rec.errors[0].Message != "testing" {
+		t.Errorf("unexpected errors %v", rec.errors)
+	}
+}
+
+func TestInvalidObject(t *testing.T) {
+	NewServer(t)
+	display, _, rec := connect(t)
+
+	// a request for an object the server never heard of
+	region := wl.NewRegion(display.Context())
+	region.Destroy()
+	display.Context().Flush()
+	<-display.Context().Done()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.errors) != 1 || rec.errors[0].Code != uint32(wl.DisplayErrorInvalidObject) {
+		t.Errorf("unexpected errors %v", rec.errors)
+	}
+}
+
+func TestProtocolError(t *testing.T) {
+	s := NewServer(t)
+	display, registry, _ := connect(t)
+	base := xdg.NewWmBase(display.Context())
+	registry.Bind(7, "xdg_wm_base", 2, base)
+	display.Context().Flush()
+
+	obj := s.Next("wl_registry.bind").Args[1].(*Object)
+	obj.PostError(uint32(xdg.WmBaseErrorDefunctSurfaces), "surfaces left")
+	err := display.Roundtrip(context.Background())
+
+	var perr *wl.ProtocolError
+	if !errors.As(err, &perr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := wl.ProtocolError{
+		Interface: "xdg_wm_base",
+		ObjectId:  base.Id(),
+		Code:      uint32(xdg.WmBaseErrorDefunctSurfaces),
+		Name:      "xdg_wm_base.defunct_surfaces",
+		Message:   "surfaces left",
+	}
+	if *perr != want {
+		t.Errorf("got %+v, expected %+v", *perr, want)
+	}
+	if display.Context().Err() != err {
+		t.Errorf("the context ended with %v", display.Context().Err())
+	}
+}
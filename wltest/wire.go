@@ -0,0 +1,60 @@
+package wltest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dkolbly/wl"
+	"github.com/dkolbly/wl/internal/wire"
+)
+
+// encodeArg appends the argument v described by arg.
+func encodeArg(e *wire.Encoder, arg wl.Arg, v interface{}) error {
+	ok := true
+	switch arg.Type {
+	case wl.ArgInt:
+		var i int32
+		i, ok = v.(int32)
+		e.PutInt32(i)
+	case wl.ArgUint:
+		var u uint32
+		u, ok = v.(uint32)
+		e.PutUint32(u)
+	case wl.ArgFixed:
+		var f wl.Fixed
+		f, ok = v.(wl.Fixed)
+		e.PutFixed(f)
+	case wl.ArgString:
+		var s string
+		s, ok = v.(string)
+		e.PutString(s)
+	case wl.ArgObject, wl.ArgNewId:
+		var o *Object
+		o, ok = v.(*Object)
+		if ok && o != nil {
+			e.PutUint32(o.Id)
+		} else {
+			ok = ok && arg.AllowNull
+			e.PutUint32(0)
+		}
+	case wl.ArgArray:
+		var a []byte
+		a, ok = v.([]byte)
+		e.PutArray(a)
+	case wl.ArgFd:
+		switch fd := v.(type) {
+		case *os.File:
+			e.PutFd(int(fd.Fd()))
+		case uintptr:
+			e.PutFd(int(fd))
+		case int:
+			e.PutFd(fd)
+		default:
+			ok = false
+		}
+	}
+	if !ok {
+		return fmt.Errorf("argument %s: unexpected value %#v", arg.Name, v)
+	}
+	return nil
+}
@@ -1,3 +1,6 @@
+// Package zxdg is the client side of the xdg_shell_unstable_v6
+// protocol, generated from protocol/xdg-shell-unstable-v6.xml with the
+// _v6 suffix stripped from the names.
 package zxdg
 
-//go:generate go run github.com/dkolbly/wl-scanner -pkg zxdg -unstable v6 -source https://raw.githubusercontent.com/wayland-project/wayland-protocols/master/unstable/xdg-shell/xdg-shell-unstable-v6.xml -output shell.go
+//go:generate go run ../cmd/wl-scanner -pkg zxdg -unstable v6 -source ../protocol/xdg-shell-unstable-v6.xml -output shell.go
@@ -1,3 +1,5 @@
+// Package xdg is the client side of the xdg_shell protocol, generated
+// from protocol/xdg-shell.xml.
 package xdg
 
-//go:generate go run github.com/dkolbly/wl-scanner -pkg xdg -source https://raw.githubusercontent.com/wayland-project/wayland-protocols/master/stable/xdg-shell/xdg-shell.xml -output shell.go
+//go:generate go run ../cmd/wl-scanner -pkg xdg -source ../protocol/xdg-shell.xml -output shell.go